@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository/postgres"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+)
+
+func main() {
+	var (
+		cmd    = flag.String("command", "list", "api key command: create|list|revoke|rotate")
+		dsn    = flag.String("database", os.Getenv("TODO_DB_DSN"), "database DSN (defaults to TODO_DB_DSN)")
+		tenant = flag.String("tenant", "", "tenant ID for create, optional filter for list")
+		name   = flag.String("name", "", "key name for create")
+		scopes = flag.String("scopes", "", "comma-separated scopes for create, e.g. todo:read,todo:create")
+		ttl    = flag.Duration("ttl", 0, "key lifetime for create; 0 never expires")
+		id     = flag.String("id", "", "key ID for revoke and rotate")
+		grace  = flag.Duration("grace", 24*time.Hour, "how long the old key stays valid after rotate")
+	)
+	flag.Parse()
+
+	if *dsn == "" {
+		fmt.Fprintln(os.Stderr, "database DSN is required")
+		os.Exit(1)
+	}
+	db, err := sql.Open("pgx", *dsn)
+	if err != nil {
+		log.Fatalf("db open error: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	keys := service.NewAPIKeyService(postgres.NewAPIKeyStore(db))
+
+	switch *cmd {
+	case "create":
+		key, raw, err := keys.Create(ctx, service.CreateAPIKeyInput{
+			TenantID: *tenant,
+			Name:     *name,
+			Scopes:   splitList(*scopes),
+			TTL:      *ttl,
+		})
+		if err != nil {
+			log.Fatalf("create error: %v", err)
+		}
+		printKey(key)
+		fmt.Printf("key=%s\n", raw)
+		fmt.Fprintln(os.Stderr, "store the key now; it cannot be shown again")
+	case "list":
+		list, err := keys.List(ctx, *tenant)
+		if err != nil {
+			log.Fatalf("list error: %v", err)
+		}
+		for _, key := range list {
+			printKey(key)
+		}
+	case "revoke":
+		revoked, err := keys.Revoke(ctx, parseID(*id))
+		if err != nil {
+			log.Fatalf("revoke error: %v", err)
+		}
+		fmt.Printf("revoked=%v\n", revoked)
+	case "rotate":
+		key, raw, err := keys.Rotate(ctx, parseID(*id), *grace)
+		if err != nil {
+			log.Fatalf("rotate error: %v", err)
+		}
+		printKey(key)
+		fmt.Printf("key=%s\n", raw)
+		fmt.Fprintf(os.Stderr, "the old key stays valid for %s\n", *grace)
+	default:
+		log.Fatalf("unknown command: %s", *cmd)
+	}
+}
+
+func printKey(key model.APIKey) {
+	status := "active"
+	if !key.Active(time.Now()) {
+		status = "inactive"
+	}
+	fmt.Printf("id=%s tenant=%s name=%q prefix=%s scopes=%s expires=%s last_used=%s status=%s\n",
+		key.ID, key.TenantID, key.Name, key.Prefix, strings.Join(key.Scopes, ","),
+		formatTime(key.ExpiresAt), formatTime(key.LastUsedAt), status)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func parseID(value string) uuid.UUID {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		log.Fatalf("invalid -id: %v", err)
+	}
+	return id
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
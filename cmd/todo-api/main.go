@@ -4,16 +4,27 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"expvar"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"google.golang.org/grpc"
+	channelz "google.golang.org/grpc/channelz/service"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
 
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/authz"
 	"github.com/fuzail-ahmed/codex-test/internal/config"
+	"github.com/fuzail-ahmed/codex-test/internal/events"
+	"github.com/fuzail-ahmed/codex-test/internal/repository/breaker"
 	"github.com/fuzail-ahmed/codex-test/internal/repository/postgres"
 	"github.com/fuzail-ahmed/codex-test/internal/server"
 	"github.com/fuzail-ahmed/codex-test/internal/service"
@@ -27,39 +38,213 @@ func main() {
 		log.Fatalf("config error: %v", err)
 	}
 
-	db, err := sql.Open("pgx", cfg.DBDSN)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := openDB(cfg.DBDSN, cfg)
 	if err != nil {
 		log.Fatalf("db open error: %v", err)
 	}
 	defer db.Close()
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
-
 	if err := db.PingContext(ctx); err != nil {
 		log.Fatalf("db ping error: %v", err)
 	}
 
-	repo := postgres.New(db)
-	svc := service.New(repo, cfg.WorkerCount)
+	replicas := make([]*sql.DB, 0, len(cfg.DBReplicaDSNs))
+	for _, dsn := range cfg.DBReplicaDSNs {
+		replica, err := openDB(dsn, cfg)
+		if err != nil {
+			log.Fatalf("db replica open error: %v", err)
+		}
+		defer replica.Close()
+		replicas = append(replicas, replica)
+	}
+
+	isolation, err := postgres.ParseIsolationLevel(cfg.DBIsolationLevel)
+	if err != nil {
+		log.Fatalf("config error: %v", err)
+	}
+	repo := postgres.New(db,
+		postgres.WithIsolationLevel(isolation),
+		postgres.WithRetryPolicy(postgres.RetryPolicy{
+			MaxAttempts: cfg.DBRetryAttempts,
+			BaseDelay:   cfg.DBRetryBaseDelay,
+			MaxDelay:    cfg.DBRetryMaxDelay,
+		}),
+		postgres.WithReplicas(replicas...),
+		postgres.WithReadYourWrites(cfg.DBReadYourWritesWindow),
+		postgres.WithQueryTimeout(cfg.DBQueryTimeout),
+	)
+	repo.StartReplicaHealthChecks(ctx, cfg.DBReplicaHealthInterval)
+	guarded := breaker.New(repo, breaker.Config{
+		FailureThreshold: cfg.BreakerFailureThreshold,
+		OpenTimeout:      cfg.BreakerOpenTimeout,
+		HalfOpenProbes:   cfg.BreakerHalfOpenProbes,
+		MaxInFlight:      cfg.DBMaxInFlight,
+	})
+	// A single instance publishes events in-process. With several, events
+	// go through a table every instance polls, so watchers see changes made
+	// through any of them.
+	broker := events.NewBroker()
+	if cfg.EventsShared {
+		eventLog := postgres.NewEventLog(db, cfg.EventsRetention)
+		eventLog.StartPrune(ctx, time.Minute)
+		broker = events.NewBroker(events.WithLog(eventLog, cfg.EventsPollInterval))
+	}
+	if err := broker.Start(ctx); err != nil {
+		log.Fatalf("event log error: %v", err)
+	}
+	// Expired idempotency keys are swept once a minute, as the memory store
+	// does.
+	idempotency := postgres.NewIdempotencyStore(db)
+	idempotency.StartSweep(ctx, time.Minute)
+	userRepo := postgres.NewUserRepo(db)
+	users := service.NewUserService(userRepo)
+	svc := service.New(guarded, cfg.WorkerCount,
+		service.WithIdempotency(idempotency, cfg.IdempotencyTTL),
+		service.WithTenantQuotas(cfg.TenantDefaultQuota, cfg.TenantQuotas),
+		service.WithUsers(userRepo),
+		service.WithACL(repo),
+		service.WithEvents(broker),
+		service.WithImportBatchSize(cfg.ImportBatchSize),
+	)
+
+	authorizer := authz.AllowAll
+	if cfg.AuthzDisabled {
+		log.Printf("TODO_AUTHZ_DISABLED is set, authorization is disabled")
+	} else {
+		policy, err := authz.LoadPolicy(cfg.AuthzPolicyFile)
+		if err != nil {
+			log.Fatalf("authz policy error: %v", err)
+		}
+		authorizer = policy
+	}
 
 	mux := http.NewServeMux()
-	handler := httptransport.NewHandler(svc)
+	handler := httptransport.NewHandler(svc, httptransport.WithAuthorizer(authorizer))
 	handler.Register(mux)
+	httptransport.NewUserHandler(users, httptransport.WithAuthorizer(authorizer)).Register(mux)
+
+	// API keys are checked first, then bearer tokens, then TLS client
+	// certificates; the first one that identifies the caller wins.
+	apiKeys := service.NewAPIKeyService(postgres.NewAPIKeyStore(db))
+	var root http.Handler = mux
+	if cfg.JWTEnabled() || cfg.TLSClientCAFile != "" {
+		// Only verified credentials name the tenant; the header is
+		// trusted in unauthenticated deployments alone.
+		root = httptransport.IgnoreUnverifiedTenant(root)
+	}
+	grpcOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(grpcserver.UnaryAPIKeyInterceptor(apiKeys)),
+		grpc.ChainStreamInterceptor(grpcserver.StreamAPIKeyInterceptor(apiKeys)),
+	}
+	var certs *server.CertReloader
+	if cfg.TLSEnabled() {
+		certs, err = server.NewCertReloader(server.TLSConfig{
+			CertFile:          cfg.TLSCertFile,
+			KeyFile:           cfg.TLSKeyFile,
+			ClientCAFile:      cfg.TLSClientCAFile,
+			RequireClientCert: cfg.TLSRequireClientCert,
+		})
+		if err != nil {
+			log.Fatalf("tls config error: %v", err)
+		}
+		certs.Start(ctx, cfg.TLSReloadInterval)
+		if cfg.TLSClientCAFile != "" {
+			root = httptransport.AuthenticateClientCert(root)
+		}
+	}
+	if cfg.JWTEnabled() {
+		verifier, err := auth.NewJWTVerifier(auth.JWTConfig{
+			HMACSecret: []byte(cfg.JWTHMACSecret),
+			JWKSFile:   cfg.JWTJWKSFile,
+			Issuer:     cfg.JWTIssuer,
+			Audience:   cfg.JWTAudience,
+			Leeway:     cfg.JWTLeeway,
+		})
+		if err != nil {
+			log.Fatalf("jwt config error: %v", err)
+		}
+		verifier.StartKeyReload(ctx, cfg.JWTJWKSReloadInterval)
+		root = httptransport.Authenticate(verifier, root)
+		grpcOpts = append(grpcOpts,
+			grpc.ChainUnaryInterceptor(grpcserver.UnaryAuthInterceptor(verifier)),
+			grpc.ChainStreamInterceptor(grpcserver.StreamAuthInterceptor(verifier)),
+		)
+	}
+	if certs != nil {
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(certs.Config("h2"))))
+	}
+	if cfg.TLSClientCAFile != "" {
+		grpcOpts = append(grpcOpts,
+			grpc.ChainUnaryInterceptor(grpcserver.UnaryClientCertInterceptor()),
+			grpc.ChainStreamInterceptor(grpcserver.StreamClientCertInterceptor()),
+		)
+	}
+	if cfg.JWTEnabled() || cfg.TLSClientCAFile != "" {
+		grpcOpts = append(grpcOpts,
+			grpc.ChainUnaryInterceptor(grpcserver.UnaryIgnoreUnverifiedTenantInterceptor()),
+			grpc.ChainStreamInterceptor(grpcserver.StreamIgnoreUnverifiedTenantInterceptor()),
+		)
+	}
+
+	root = httptransport.AuthenticateAPIKey(apiKeys, root)
 
-	httpServer := server.NewHTTP(cfg.HTTPAddr, mux)
+	root = httptransport.RequestID(root)
 
-	grpcSrv, grpcLis, err := grpcserver.ListenAndServe(cfg.GRPCAddr, svc)
+	httpServer := server.NewHTTP(cfg.HTTPAddr, root)
+	if certs != nil {
+		httpServer.TLSConfig = certs.Config("h2", "http/1.1")
+	}
+
+	grpcSrv, grpcLis, err := grpcserver.ListenAndServe(cfg.GRPCAddr, svc, users, authorizer, grpcOpts...)
 	if err != nil {
 		log.Fatalf("grpc listen error: %v", err)
 	}
+	if cfg.GRPCReflection {
+		reflection.Register(grpcSrv)
+	}
+	if cfg.GRPCChannelz {
+		channelz.RegisterChannelzServiceToServer(grpcSrv)
+	}
+	grpcHealth := grpcserver.RegisterHealth(grpcSrv)
+	grpcHealth.Watch(ctx, cfg.GRPCHealthInterval, db.PingContext)
+
+	// The admin listener serves probes and operational endpoints on a
+	// separate, plaintext port that is not exposed outside the cluster.
+	adminMux := http.NewServeMux()
+	adminMux.Handle("/debug/vars", expvar.Handler())
+	httptransport.NewAdminHandler(repo.PoolStats, grpcHealth.Serving).Register(adminMux)
+	adminServer := server.NewHTTP(cfg.AdminAddr, adminMux)
 
 	go func() {
 		<-ctx.Done()
+		grpcHealth.Shutdown()
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		_ = httpServer.Shutdown(shutdownCtx)
-		grpcSrv.GracefulStop()
+		// Watch streams never end on their own; cut them off once the
+		// shutdown timeout has passed.
+		stopped := make(chan struct{})
+		go func() {
+			grpcSrv.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcSrv.Stop()
+		}
+		// Shut the admin listener last so readiness reports the drain.
+		_ = adminServer.Shutdown(shutdownCtx)
+	}()
+
+	go func() {
+		log.Printf("admin listening on %s", cfg.AdminAddr)
+		if err := adminServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("admin server error: %v", err)
+		}
 	}()
 
 	go func() {
@@ -69,8 +254,32 @@ func main() {
 		}
 	}()
 
-	log.Printf("http listening on %s", cfg.HTTPAddr)
-	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	log.Printf("http listening on %s (tls=%t)", cfg.HTTPAddr, certs != nil)
+	if certs != nil {
+		err = httpServer.ListenAndServeTLS("", "")
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("http server error: %v", err)
 	}
 }
+
+// openDB builds a pool with the configured limits. The statement timeout is
+// sent as a runtime parameter so Postgres cancels runaway queries itself.
+func openDB(dsn string, cfg config.Config) (*sql.DB, error) {
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DBStatementTimeout > 0 {
+		connConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.DBStatementTimeout.Milliseconds(), 10)
+	}
+
+	db := stdlib.OpenDB(*connConfig)
+	db.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	db.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	db.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+	return db, nil
+}
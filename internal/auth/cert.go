@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"crypto/x509"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+// PrincipalFromCertificate maps a verified client certificate to a
+// principal. A subject common name that is a UUID becomes the user ID;
+// otherwise the ID is a name-based UUID derived from the full subject, which
+// stays stable across certificate renewals. Organizational units become
+// groups. A single organization that is a valid tenant ID is returned as
+// the caller's tenant; otherwise the tenant is empty.
+func PrincipalFromCertificate(cert *x509.Certificate) (Principal, string) {
+	userID, err := uuid.Parse(cert.Subject.CommonName)
+	if err != nil || userID == uuid.Nil {
+		userID = uuid.NewSHA1(uuid.NameSpaceX500, []byte(cert.Subject.String()))
+	}
+	var tenantID string
+	if orgs := cert.Subject.Organization; len(orgs) == 1 && tenant.Validate(orgs[0]) == nil {
+		tenantID = orgs[0]
+	}
+	return Principal{UserID: userID, Groups: cert.Subject.OrganizationalUnit}, tenantID
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+var (
+	// ErrInvalidToken is returned for bearer tokens that fail verification.
+	ErrInvalidToken = errors.New("invalid bearer token")
+	// ErrInvalidAPIKey is returned for unknown, revoked or expired API keys.
+	ErrInvalidAPIKey = errors.New("invalid api key")
+)
+
+// Verifier turns a bearer token into the principal it identifies and the
+// tenant the token was issued for.
+type Verifier interface {
+	Verify(token string) (Principal, string, error)
+}
+
+// APIKeyAuthenticator resolves an API key to its principal and the tenant
+// the key belongs to.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (Principal, string, error)
+}
+
+type JWTConfig struct {
+	// HMACSecret enables HS256 tokens signed with a shared secret.
+	HMACSecret []byte
+	// JWKSFile enables RS256 and ES256 tokens signed by keys in a local JSON
+	// Web Key Set, selected by the token's kid header.
+	JWKSFile string
+	Issuer   string
+	Audience string
+	// Leeway tolerates clock skew when checking exp, nbf and iat.
+	Leeway time.Duration
+}
+
+// JWTVerifier validates signature, issuer, audience and expiry, and maps
+// sub to Principal.UserID and the groups and roles claims to their fields.
+// The tenant_id claim is required and names the caller's tenant.
+type JWTVerifier struct {
+	cfg     JWTConfig
+	keys    atomic.Pointer[map[string]crypto.PublicKey]
+	parser  *jwt.Parser
+	methods []string
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	TenantID string   `json:"tenant_id"`
+	Groups   []string `json:"groups"`
+	Roles    []string `json:"roles"`
+}
+
+func NewJWTVerifier(cfg JWTConfig) (*JWTVerifier, error) {
+	v := &JWTVerifier{cfg: cfg}
+	if len(cfg.HMACSecret) > 0 {
+		v.methods = append(v.methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.JWKSFile != "" {
+		v.methods = append(v.methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+		if err := v.ReloadKeys(); err != nil {
+			return nil, err
+		}
+	}
+	if len(v.methods) == 0 {
+		return nil, errors.New("jwt: an HMAC secret or a JWKS file is required")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(v.methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+	return v, nil
+}
+
+// ReloadKeys rereads the JWKS file. On failure the previous keys stay in use.
+func (v *JWTVerifier) ReloadKeys() error {
+	keys, err := loadJWKS(v.cfg.JWKSFile)
+	if err != nil {
+		return err
+	}
+	v.keys.Store(&keys)
+	return nil
+}
+
+// StartKeyReload rereads the JWKS file every interval until ctx is done, so
+// rotated keys are picked up without a restart.
+func (v *JWTVerifier) StartKeyReload(ctx context.Context, interval time.Duration) {
+	if v.cfg.JWKSFile == "" || interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := v.ReloadKeys(); err != nil {
+					log.Printf("jwks reload failed, keeping previous keys: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (v *JWTVerifier) Verify(token string) (Principal, string, error) {
+	var c claims
+	if _, err := v.parser.ParseWithClaims(token, &c, v.key); err != nil {
+		return Principal{}, "", fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	userID, err := uuid.Parse(c.Subject)
+	if err != nil || userID == uuid.Nil {
+		return Principal{}, "", fmt.Errorf("%w: sub must be a user id", ErrInvalidToken)
+	}
+	if err := tenant.Validate(c.TenantID); err != nil {
+		return Principal{}, "", fmt.Errorf("%w: tenant_id: %v", ErrInvalidToken, err)
+	}
+	return Principal{UserID: userID, Groups: c.Groups, Roles: c.Roles}, c.TenantID, nil
+}
+
+func (v *JWTVerifier) key(token *jwt.Token) (any, error) {
+	if token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+		return v.cfg.HMACSecret, nil
+	}
+	keys := v.keys.Load()
+	if keys == nil {
+		return nil, errors.New("no signing keys loaded")
+	}
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" && len(*keys) == 1 {
+		for _, key := range *keys {
+			return key, nil
+		}
+	}
+	key, ok := (*keys)[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	return key, nil
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestJWTVerifier(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "EC",
+		"kid": "k1",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))),
+		"y":   base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32))),
+	}}})
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(jwksFile, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	verifier, err := NewJWTVerifier(JWTConfig{HMACSecret: secret, JWKSFile: jwksFile, Issuer: "issuer", Audience: "todo-api"})
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+
+	userID := uuid.New()
+	claimsFor := func(mutate func(jwt.MapClaims)) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub":       userID.String(),
+			"iss":       "issuer",
+			"aud":       "todo-api",
+			"exp":       time.Now().Add(time.Minute).Unix(),
+			"tenant_id": "acme",
+			"roles":     []string{"admin"},
+		}
+		if mutate != nil {
+			mutate(c)
+		}
+		return c
+	}
+	hs256 := func(c jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString(secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	es256 := func(kid string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claimsFor(nil))
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(ecKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	principal, tenantID, err := verifier.Verify(hs256(claimsFor(nil)))
+	if err != nil {
+		t.Fatalf("hs256: %v", err)
+	}
+	if principal.UserID != userID || len(principal.Roles) != 1 || principal.Roles[0] != "admin" {
+		t.Fatalf("unexpected principal %+v", principal)
+	}
+	if tenantID != "acme" {
+		t.Fatalf("expected tenant acme, got %q", tenantID)
+	}
+	if _, _, err := verifier.Verify(es256("k1")); err != nil {
+		t.Fatalf("es256: %v", err)
+	}
+
+	for name, token := range map[string]string{
+		"expired":       hs256(claimsFor(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() })),
+		"no expiry":     hs256(claimsFor(func(c jwt.MapClaims) { delete(c, "exp") })),
+		"wrong issuer":  hs256(claimsFor(func(c jwt.MapClaims) { c["iss"] = "other" })),
+		"wrong aud":     hs256(claimsFor(func(c jwt.MapClaims) { c["aud"] = "other" })),
+		"bad subject":   hs256(claimsFor(func(c jwt.MapClaims) { c["sub"] = "alice" })),
+		"unknown kid":   es256("k2"),
+		"wrong secret":  mustSign(t, jwt.SigningMethodHS256, claimsFor(nil), []byte("another-secret-another-secret-xx")),
+		"alg none":      mustSign(t, jwt.SigningMethodNone, claimsFor(nil), jwt.UnsafeAllowNoneSignatureType),
+		"not a jwt":     "garbage",
+		"empty subject": hs256(claimsFor(func(c jwt.MapClaims) { delete(c, "sub") })),
+		"no tenant":     hs256(claimsFor(func(c jwt.MapClaims) { delete(c, "tenant_id") })),
+		"bad tenant":    hs256(claimsFor(func(c jwt.MapClaims) { c["tenant_id"] = "acme corp" })),
+	} {
+		if _, _, err := verifier.Verify(token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+}
+
+func mustSign(t *testing.T, method jwt.SigningMethod, c jwt.MapClaims, key any) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, c).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// UserHeader identifies the calling user on HTTP requests.
+	UserHeader = "X-User-ID"
+	// UserMetadataKey identifies the calling user on gRPC requests.
+	UserMetadataKey = "x-user-id"
+	// APIKeyHeader and APIKeyMetadataKey carry an API key.
+	APIKeyHeader      = "X-API-Key"
+	APIKeyMetadataKey = "x-api-key"
+)
+
+// Principal is the caller of a request. Groups and Roles only ever come
+// from verified credentials; callers named by the X-User-ID header have
+// neither and are marked Unverified.
+type Principal struct {
+	UserID uuid.UUID
+	Groups []string
+	Roles  []string
+	// Unverified is set when nothing but a header vouches for UserID. The
+	// authorization policy treats such callers as anonymous.
+	Unverified bool
+	// Scopes, when non-nil, limits the caller to the listed actions on top
+	// of what its roles allow. API keys set it; users leave it nil.
+	Scopes []string
+}
+
+type contextKey struct{}
+
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// PrincipalFromContext returns the caller and whether one was set.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(contextKey{}).(Principal)
+	return principal, ok
+}
+
+// UserID returns the caller's user ID, or uuid.Nil for anonymous requests.
+func UserID(ctx context.Context) uuid.UUID {
+	principal, _ := PrincipalFromContext(ctx)
+	return principal.UserID
+}
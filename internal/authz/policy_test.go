@@ -0,0 +1,50 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+)
+
+func TestPolicyAuthorize(t *testing.T) {
+	policy, err := LoadPolicy("../../configs/authz-policy.json")
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	as := func(roles ...string) context.Context {
+		return auth.WithPrincipal(context.Background(), auth.Principal{UserID: uuid.New(), Roles: roles})
+	}
+
+	headerOnly := auth.WithPrincipal(context.Background(), auth.Principal{UserID: uuid.New(), Unverified: true})
+
+	cases := []struct {
+		name   string
+		ctx    context.Context
+		action Action
+		allow  bool
+	}{
+		{"admin wildcard", as("admin"), ActionUserCreate, true},
+		{"member deletes", as("member"), ActionTodoDelete, true},
+		{"member cannot create users", as("member"), ActionUserCreate, false},
+		{"read-only reads", as("read-only"), ActionTodoRead, true},
+		{"read-only cannot delete", as("read-only"), ActionTodoDelete, false},
+		{"any role may allow", as("read-only", "member"), ActionTodoCreate, true},
+		{"no roles falls back to default", as(), ActionTodoCreate, true},
+		{"unknown role", as("intern"), ActionTodoRead, false},
+		{"anonymous", context.Background(), ActionTodoRead, false},
+		{"header-only caller is anonymous", headerOnly, ActionTodoDelete, false},
+	}
+	for _, tc := range cases {
+		err := policy.Authorize(tc.ctx, tc.action)
+		if tc.allow && err != nil {
+			t.Errorf("%s: unexpected denial: %v", tc.name, err)
+		}
+		if !tc.allow && !errors.Is(err, ErrDenied) {
+			t.Errorf("%s: expected ErrDenied, got %v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Package errcode is the catalog of machine-readable error codes returned by
+// the HTTP and gRPC APIs, and the single mapping from service and repository
+// errors to those codes. Codes are stable; clients switch on them instead of
+// parsing messages.
+package errcode
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/authz"
+	"github.com/fuzail-ahmed/codex-test/internal/events"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+type Code string
+
+// Domain qualifies the codes in gRPC ErrorInfo. The catalog is shared by
+// every API version, so the domain names the service, not a version.
+const Domain = "todo-api"
+
+const (
+	ValidationFailed     Code = "VALIDATION_FAILED"
+	InvalidArgument      Code = "INVALID_ARGUMENT"
+	TenantRequired       Code = "TENANT_REQUIRED"
+	Unauthenticated      Code = "UNAUTHENTICATED"
+	PermissionDenied     Code = "PERMISSION_DENIED"
+	NotFound             Code = "NOT_FOUND"
+	MethodNotAllowed     Code = "METHOD_NOT_ALLOWED"
+	Conflict             Code = "CONFLICT"
+	IdempotencyKeyReused Code = "IDEMPOTENCY_KEY_REUSED"
+	ConcurrentUpdate     Code = "CONCURRENT_UPDATE"
+	ReferenceNotFound    Code = "REFERENCE_NOT_FOUND"
+	ConstraintViolation  Code = "CONSTRAINT_VIOLATION"
+	QuotaExceeded        Code = "QUOTA_EXCEEDED"
+	ResumeExpired        Code = "RESUME_EXPIRED"
+	SlowConsumer         Code = "SLOW_CONSUMER"
+	Timeout              Code = "TIMEOUT"
+	Canceled             Code = "CANCELED"
+	Unavailable          Code = "UNAVAILABLE"
+	OutcomeUnknown       Code = "OUTCOME_UNKNOWN"
+	Internal             Code = "INTERNAL"
+)
+
+// StatusClientClosedRequest is the de facto HTTP status for requests the
+// client abandoned.
+const StatusClientClosedRequest = 499
+
+// Entry describes how a code is presented by each transport.
+type Entry struct {
+	Title      string
+	HTTPStatus int
+	GRPCCode   codes.Code
+}
+
+var catalog = map[Code]Entry{
+	ValidationFailed:     {"Request validation failed", http.StatusBadRequest, codes.InvalidArgument},
+	InvalidArgument:      {"Invalid request", http.StatusBadRequest, codes.InvalidArgument},
+	TenantRequired:       {"Missing or invalid tenant", http.StatusBadRequest, codes.InvalidArgument},
+	Unauthenticated:      {"Authentication failed", http.StatusUnauthorized, codes.Unauthenticated},
+	PermissionDenied:     {"Permission denied", http.StatusForbidden, codes.PermissionDenied},
+	NotFound:             {"Resource not found", http.StatusNotFound, codes.NotFound},
+	MethodNotAllowed:     {"Method not allowed", http.StatusMethodNotAllowed, codes.Unimplemented},
+	Conflict:             {"Resource already exists", http.StatusConflict, codes.AlreadyExists},
+	IdempotencyKeyReused: {"Idempotency key reused", http.StatusConflict, codes.AlreadyExists},
+	ConcurrentUpdate:     {"Concurrent update", http.StatusConflict, codes.Aborted},
+	ReferenceNotFound:    {"Referenced resource not found", http.StatusUnprocessableEntity, codes.FailedPrecondition},
+	ConstraintViolation:  {"Constraint violation", http.StatusBadRequest, codes.InvalidArgument},
+	QuotaExceeded:        {"Quota exceeded", http.StatusTooManyRequests, codes.ResourceExhausted},
+	ResumeExpired:        {"Resume token expired", http.StatusGone, codes.OutOfRange},
+	SlowConsumer:         {"Subscriber fell behind", http.StatusTooManyRequests, codes.ResourceExhausted},
+	Timeout:              {"Request timed out", http.StatusGatewayTimeout, codes.DeadlineExceeded},
+	Canceled:             {"Request canceled", StatusClientClosedRequest, codes.Canceled},
+	Unavailable:          {"Service unavailable", http.StatusServiceUnavailable, codes.Unavailable},
+	OutcomeUnknown:       {"Outcome unknown", http.StatusInternalServerError, codes.Unknown},
+	Internal:             {"Internal error", http.StatusInternalServerError, codes.Internal},
+}
+
+// Lookup returns the catalog entry for code, falling back to Internal for
+// codes that are not in the catalog.
+func Lookup(code Code) Entry {
+	if entry, ok := catalog[code]; ok {
+		return entry
+	}
+	return catalog[Internal]
+}
+
+// TypeURI is the RFC 7807 problem type for code, e.g.
+// "/problems/validation-failed".
+func TypeURI(code Code) string {
+	return "/problems/" + strings.ReplaceAll(strings.ToLower(string(code)), "_", "-")
+}
+
+// Of classifies err and returns its code together with a message that is
+// safe to show to clients. Unrecognised errors become Internal so their
+// details do not leak.
+func Of(err error) (Code, string) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		return ValidationFailed, err.Error()
+	case errors.Is(err, tenant.ErrMissing), errors.Is(err, tenant.ErrInvalid):
+		return TenantRequired, err.Error()
+	case errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrInvalidAPIKey):
+		return Unauthenticated, err.Error()
+	case errors.Is(err, service.ErrPermissionDenied), errors.Is(err, authz.ErrDenied):
+		return PermissionDenied, err.Error()
+	case errors.Is(err, service.ErrQuotaExceeded):
+		return QuotaExceeded, err.Error()
+	case errors.Is(err, events.ErrResumeExpired):
+		return ResumeExpired, err.Error()
+	case errors.Is(err, events.ErrSlowConsumer):
+		return SlowConsumer, err.Error()
+	case errors.Is(err, repository.ErrNotFound):
+		return NotFound, "not found"
+	case errors.Is(err, repository.ErrConflict):
+		return Conflict, "conflict"
+	case errors.Is(err, service.ErrIdempotencyKeyReused):
+		return IdempotencyKeyReused, err.Error()
+	case errors.Is(err, repository.ErrForeignKeyViolation):
+		return ReferenceNotFound, "referenced resource does not exist"
+	case errors.Is(err, repository.ErrCheckViolation):
+		return ConstraintViolation, "constraint violation"
+	case errors.Is(err, repository.ErrSerialization):
+		return ConcurrentUpdate, "concurrent update, please retry"
+	case errors.Is(err, repository.ErrQueryCanceled):
+		return Timeout, "query canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return Timeout, "deadline exceeded"
+	case errors.Is(err, context.Canceled):
+		return Canceled, "request canceled"
+	case errors.Is(err, repository.ErrOutcomeUnknown):
+		return OutcomeUnknown, "database connection lost while committing; the change may or may not have been applied"
+	case errors.Is(err, repository.ErrUnavailable):
+		return Unavailable, "service temporarily unavailable"
+	case errors.Is(err, repository.ErrConnectionLost):
+		return Unavailable, "database unavailable"
+	default:
+		return Internal, "internal error"
+	}
+}
@@ -0,0 +1,47 @@
+package errcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+)
+
+func TestOf(t *testing.T) {
+	cases := []struct {
+		err  error
+		want Code
+	}{
+		{fmt.Errorf("%w: title is required", service.ErrValidation), ValidationFailed},
+		{fmt.Errorf("get: %w", repository.ErrNotFound), NotFound},
+		{&repository.UnavailableError{Reason: "breaker open"}, Unavailable},
+		{fmt.Errorf("%w: %v", repository.ErrOutcomeUnknown, repository.ErrConnectionLost), OutcomeUnknown},
+		{context.Canceled, Canceled},
+		{errors.New("pq: something private"), Internal},
+	}
+	for _, tc := range cases {
+		code, detail := Of(tc.err)
+		if code != tc.want {
+			t.Errorf("%v: expected %s, got %s", tc.err, tc.want, code)
+		}
+		if code == Internal && detail != "internal error" {
+			t.Errorf("internal error leaked detail %q", detail)
+		}
+	}
+}
+
+func TestLookupAndTypeURI(t *testing.T) {
+	if got := Lookup(ValidationFailed).HTTPStatus; got != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", got)
+	}
+	if got := Lookup("NOT_A_CODE"); got != Lookup(Internal) {
+		t.Fatalf("expected unknown codes to fall back to INTERNAL, got %+v", got)
+	}
+	if got := TypeURI(IdempotencyKeyReused); got != "/problems/idempotency-key-reused" {
+		t.Fatalf("unexpected type uri %q", got)
+	}
+}
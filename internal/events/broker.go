@@ -0,0 +1,400 @@
+// Package events is a pub/sub broker for todo change events. It keeps a
+// bounded history so reconnecting subscribers can resume from the last event
+// they saw. On its own the broker is in-process; with a Log it shares events
+// between every instance that uses the same log.
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"expvar"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+)
+
+var (
+	// ErrResumeExpired is returned for resume tokens that are older than the
+	// retained history or were issued before a restart. Clients should
+	// reload their state and watch again without a token.
+	ErrResumeExpired = errors.New("resume token expired")
+	// ErrSlowConsumer ends a subscription whose buffer filled up. Clients
+	// can resume from the last token they received.
+	ErrSlowConsumer = errors.New("subscriber too slow, events dropped")
+)
+
+type Type string
+
+const (
+	TypeCreated Type = "created"
+	TypeUpdated Type = "updated"
+	TypeDeleted Type = "deleted"
+)
+
+// Event is a change to a single todo. Todo is the state after the change,
+// or the last state for deletions. Grants is the todo's sharing list at the
+// time, so subscribers can be filtered without further lookups.
+type Event struct {
+	Seq    uint64
+	Type   Type
+	Todo   model.Todo
+	Grants []model.Grant
+	Time   time.Time
+	token  string
+}
+
+// Token is the resume token that replays events after this one.
+func (e Event) Token() string {
+	return e.token
+}
+
+// Log is a durable, ordered event log shared by several brokers.
+// Sequence numbers increase in the order events become visible to Read, but
+// may have gaps.
+type Log interface {
+	// Append stores the events in order and assigns their sequence numbers.
+	Append(ctx context.Context, events []Event) error
+	// Read returns up to limit events after the sequence number, oldest
+	// first.
+	Read(ctx context.Context, after uint64, limit int) ([]Event, error)
+	// Tail returns the newest n events, oldest first.
+	Tail(ctx context.Context, n int) ([]Event, error)
+}
+
+const (
+	// logEpoch prefixes resume tokens of log-backed brokers. Sequence
+	// numbers come from the log, so tokens are valid on every instance and
+	// across restarts.
+	logEpoch = "log"
+	// appendTimeout bounds each append to the log.
+	appendTimeout = 5 * time.Second
+	// appendQueue is how many published batches may wait for the log
+	// before further ones are dropped.
+	appendQueue = 1024
+	// pollBatch is how many events a poll reads from the log at a time.
+	pollBatch = 256
+)
+
+// droppedEvents counts events that never reached the log and is published
+// through expvar as "events_dropped".
+var droppedEvents = expvar.NewInt("events_dropped")
+
+// errAppendQueueFull drops events published faster than the log takes them.
+var errAppendQueueFull = errors.New("append queue full")
+
+type Broker struct {
+	mu         sync.Mutex
+	epoch      string
+	seq        uint64
+	history    []Event
+	historyCap int
+	// floor is the newest sequence number no longer in history; tokens
+	// before it cannot be resumed.
+	floor      uint64
+	bufferSize int
+	subs       map[*Subscription]struct{}
+	now        func() time.Time
+
+	log          Log
+	pollInterval time.Duration
+	appends      chan []Event
+}
+
+type Option func(*Broker)
+
+// WithHistory sets how many recent events are kept for resuming.
+func WithHistory(n int) Option {
+	return func(b *Broker) {
+		b.historyCap = n
+	}
+}
+
+// WithBuffer sets how many undelivered events a subscriber may queue before
+// it is dropped as a slow consumer.
+func WithBuffer(n int) Option {
+	return func(b *Broker) {
+		b.bufferSize = n
+	}
+}
+
+// WithLog publishes through log and delivers the events every instance
+// appended to it, polling every pollInterval once Start is called.
+func WithLog(log Log, pollInterval time.Duration) Option {
+	return func(b *Broker) {
+		b.log = log
+		b.pollInterval = pollInterval
+		b.epoch = logEpoch
+		b.appends = make(chan []Event, appendQueue)
+	}
+}
+
+func NewBroker(opts ...Option) *Broker {
+	var epoch [4]byte
+	_, _ = rand.Read(epoch[:])
+	b := &Broker{
+		epoch:      hex.EncodeToString(epoch[:]),
+		historyCap: 1024,
+		bufferSize: 64,
+		subs:       make(map[*Subscription]struct{}),
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Publish assigns sequence numbers to the events, records them and hands
+// them to every matching subscriber without blocking. With a log, the events
+// are queued for the log instead and delivered by the poller once appended.
+// Publish never waits for the log: events that cannot be queued or appended
+// are logged, counted in events_dropped and lost.
+func (b *Broker) Publish(events ...Event) {
+	if b.log != nil {
+		b.append(events)
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, e := range events {
+		e.Seq = b.seq + 1
+		e.Time = b.now()
+		b.deliver(e)
+	}
+}
+
+func (b *Broker) append(events []Event) {
+	now := b.now()
+	batch := make([]Event, len(events))
+	for i, e := range events {
+		e.Time = now
+		batch[i] = e
+	}
+	select {
+	case b.appends <- batch:
+	default:
+		discard(len(batch), errAppendQueueFull)
+	}
+}
+
+// appendLoop writes queued batches to the log in publish order until ctx is
+// done, then makes a last attempt at whatever is still queued.
+func (b *Broker) appendLoop(ctx context.Context) {
+	write := func(batch []Event) {
+		appendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), appendTimeout)
+		defer cancel()
+		if err := b.log.Append(appendCtx, batch); err != nil {
+			discard(len(batch), err)
+		}
+	}
+	for {
+		select {
+		case batch := <-b.appends:
+			write(batch)
+		case <-ctx.Done():
+			for {
+				select {
+				case batch := <-b.appends:
+					write(batch)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func discard(n int, err error) {
+	droppedEvents.Add(int64(n))
+	log.Printf("events: dropping %d events: %v", n, err)
+}
+
+// deliver records e and hands it to every matching subscriber; b.mu must be
+// held.
+func (b *Broker) deliver(e Event) {
+	b.seq = e.Seq
+	e.token = b.epoch + "-" + strconv.FormatUint(e.Seq, 10)
+	b.history = append(b.history, e)
+	if len(b.history) > b.historyCap {
+		evicted := len(b.history) - b.historyCap
+		b.floor = b.history[evicted-1].Seq
+		b.history = b.history[evicted:]
+	}
+	for sub := range b.subs {
+		if e.Seq <= sub.after || !sub.match(e) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+			b.drop(sub, ErrSlowConsumer)
+		}
+	}
+}
+
+// Start loads the newest retained events from the log, so tokens issued
+// before a restart still resume. Until ctx is done it then appends published
+// events and polls the log every poll interval. It does nothing without a
+// log.
+func (b *Broker) Start(ctx context.Context) error {
+	if b.log == nil {
+		return nil
+	}
+	tail, err := b.log.Tail(ctx, b.historyCap)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	if len(tail) > 0 {
+		// Anything older was pruned from the log or is not in history.
+		b.floor = tail[0].Seq - 1
+	}
+	for _, e := range tail {
+		b.deliver(e)
+	}
+	b.mu.Unlock()
+
+	go b.appendLoop(ctx)
+	go func() {
+		ticker := time.NewTicker(b.pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := b.poll(ctx); err != nil && ctx.Err() == nil {
+					log.Printf("events: reading the log failed: %v", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// poll delivers every event appended to the log since the last one seen.
+// Only the poller advances b.seq in log mode.
+func (b *Broker) poll(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		after := b.seq
+		b.mu.Unlock()
+		batch, err := b.log.Read(ctx, after, pollBatch)
+		if err != nil {
+			return err
+		}
+		b.mu.Lock()
+		for _, e := range batch {
+			if e.Seq > b.seq {
+				b.deliver(e)
+			}
+		}
+		b.mu.Unlock()
+		if len(batch) < pollBatch {
+			return nil
+		}
+	}
+}
+
+// Subscribe delivers future events accepted by match. A non-empty resume
+// token first replays the retained events published after it.
+func (b *Broker) Subscribe(match func(Event) bool, resumeToken string) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var replay []Event
+	var after uint64
+	if resumeToken != "" {
+		var err error
+		after, err = b.parseToken(resumeToken)
+		if err != nil {
+			return nil, err
+		}
+		if after < b.floor {
+			return nil, ErrResumeExpired
+		}
+		for _, e := range b.history {
+			if e.Seq > after && match(e) {
+				replay = append(replay, e)
+			}
+		}
+	}
+	sub := &Subscription{
+		ch:     make(chan Event, b.bufferSize+len(replay)),
+		done:   make(chan struct{}),
+		after:  after,
+		match:  match,
+		broker: b,
+	}
+	for _, e := range replay {
+		sub.ch <- e
+	}
+	b.subs[sub] = struct{}{}
+	return sub, nil
+}
+
+func (b *Broker) parseToken(token string) (uint64, error) {
+	epoch, seq, ok := strings.Cut(token, "-")
+	if !ok || epoch != b.epoch {
+		return 0, ErrResumeExpired
+	}
+	after, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return 0, ErrResumeExpired
+	}
+	// Another instance may have handed out a token this one has not polled
+	// yet; events up to it are skipped when they arrive.
+	if after > b.seq && b.log == nil {
+		return 0, ErrResumeExpired
+	}
+	return after, nil
+}
+
+// drop removes sub and records why; b.mu must be held.
+func (b *Broker) drop(sub *Subscription, err error) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	sub.err = err
+	close(sub.done)
+}
+
+// Subscription is one subscriber's event queue.
+type Subscription struct {
+	ch     chan Event
+	done   chan struct{}
+	err    error
+	after  uint64
+	match  func(Event) bool
+	broker *Broker
+}
+
+// Events returns queued events. It is never closed; select on Done as well.
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Done is closed when the broker drops the subscription or it is closed.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err reports why the subscription ended: ErrSlowConsumer, or nil after
+// Close.
+func (s *Subscription) Err() error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	return s.err
+}
+
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.drop(s, nil)
+}
@@ -0,0 +1,119 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+)
+
+func all(Event) bool { return true }
+
+func TestSubscribeResumesAfterToken(t *testing.T) {
+	b := NewBroker()
+	b.Publish(Event{Type: TypeCreated, Todo: model.Todo{Title: "a"}})
+	sub, err := b.Subscribe(all, "")
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	b.Publish(Event{Type: TypeUpdated, Todo: model.Todo{Title: "b"}}, Event{Type: TypeDeleted, Todo: model.Todo{Title: "c"}})
+	first := <-sub.Events()
+	sub.Close()
+
+	resumed, err := b.Subscribe(all, first.Token())
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	defer resumed.Close()
+	if e := <-resumed.Events(); e.Todo.Title != "c" || e.Type != TypeDeleted {
+		t.Fatalf("expected replay of the missed event, got %+v", e)
+	}
+	if _, err := NewBroker().Subscribe(all, first.Token()); !errors.Is(err, ErrResumeExpired) {
+		t.Fatalf("expected token from another broker to be expired, got %v", err)
+	}
+}
+
+func TestSlowConsumerIsDropped(t *testing.T) {
+	b := NewBroker(WithBuffer(1), WithHistory(1))
+	sub, err := b.Subscribe(all, "")
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	b.Publish(Event{Type: TypeCreated}, Event{Type: TypeCreated}, Event{Type: TypeCreated})
+	<-sub.Done()
+	if !errors.Is(sub.Err(), ErrSlowConsumer) {
+		t.Fatalf("expected slow consumer error, got %v", sub.Err())
+	}
+	first := <-sub.Events()
+	if _, err := b.Subscribe(all, first.Token()); !errors.Is(err, ErrResumeExpired) {
+		t.Fatalf("expected token outside history to be expired, got %v", err)
+	}
+}
+
+// memoryLog is a Log shared by the brokers of a test.
+type memoryLog struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (l *memoryLog) Append(ctx context.Context, events []Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, e := range events {
+		e.Seq = uint64(len(l.events)) + 1
+		l.events = append(l.events, e)
+	}
+	return nil
+}
+
+func (l *memoryLog) Read(ctx context.Context, after uint64, limit int) ([]Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := l.events[min(int(after), len(l.events)):]
+	return append([]Event(nil), out[:min(limit, len(out))]...), nil
+}
+
+func (l *memoryLog) Tail(ctx context.Context, n int) ([]Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Event(nil), l.events[max(len(l.events)-n, 0):]...), nil
+}
+
+func TestLogSharesEventsBetweenBrokers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	shared := &memoryLog{}
+	publisher := NewBroker(WithLog(shared, time.Millisecond))
+	watcher := NewBroker(WithLog(shared, time.Millisecond))
+	for _, b := range []*Broker{publisher, watcher} {
+		if err := b.Start(ctx); err != nil {
+			t.Fatalf("start: %v", err)
+		}
+	}
+	sub, err := watcher.Subscribe(all, "")
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	publisher.Publish(Event{Type: TypeCreated, Todo: model.Todo{Title: "a"}}, Event{Type: TypeUpdated, Todo: model.Todo{Title: "b"}})
+	first := <-sub.Events()
+	if first.Todo.Title != "a" {
+		t.Fatalf("expected the other broker's event, got %+v", first)
+	}
+	sub.Close()
+
+	restarted := NewBroker(WithLog(shared, time.Millisecond))
+	if err := restarted.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	resumed, err := restarted.Subscribe(all, first.Token())
+	if err != nil {
+		t.Fatalf("resume on another broker: %v", err)
+	}
+	defer resumed.Close()
+	if e := <-resumed.Events(); e.Todo.Title != "b" {
+		t.Fatalf("expected replay of the missed event, got %+v", e)
+	}
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permission is an access level on a todo. Each level includes the ones
+// below it: owner > editor > viewer.
+type Permission string
+
+const (
+	PermissionViewer Permission = "viewer"
+	PermissionEditor Permission = "editor"
+	PermissionOwner  Permission = "owner"
+)
+
+// Rank orders permissions; unknown or empty permissions rank zero.
+func (p Permission) Rank() int {
+	switch p {
+	case PermissionViewer:
+		return 1
+	case PermissionEditor:
+		return 2
+	case PermissionOwner:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Includes reports whether p grants at least other.
+func (p Permission) Includes(other Permission) bool {
+	return p.Rank() > 0 && p.Rank() >= other.Rank()
+}
+
+type SubjectType string
+
+const (
+	SubjectUser  SubjectType = "user"
+	SubjectGroup SubjectType = "group"
+)
+
+// Grant shares a todo with a user or a group at a permission level.
+type Grant struct {
+	TodoID      uuid.UUID
+	TenantID    string
+	SubjectType SubjectType
+	// SubjectID is a user UUID or a group name, depending on SubjectType.
+	SubjectID  string
+	Permission Permission
+	CreatedAt  time.Time
+}
+
+// AppliesTo reports whether the grant covers the user or any of the groups.
+func (g Grant) AppliesTo(userID uuid.UUID, groups []string) bool {
+	switch g.SubjectType {
+	case SubjectUser:
+		return userID != uuid.Nil && g.SubjectID == userID.String()
+	case SubjectGroup:
+		return slices.Contains(groups, g.SubjectID)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey is a long-lived credential for service-to-service callers. Only a
+// hash of the secret is stored; Prefix identifies the key for lookup.
+type APIKey struct {
+	ID       uuid.UUID
+	TenantID string
+	Name     string
+	Prefix   string
+	Hash     []byte
+	// Scopes lists the actions the key may perform, e.g. "todo:read" or
+	// "todo:*".
+	Scopes []string
+	// ExpiresAt, LastUsedAt and RevokedAt are zero when unset.
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+	RevokedAt  time.Time
+	CreatedAt  time.Time
+}
+
+// Active reports whether the key is neither revoked nor expired at now.
+func (k APIKey) Active(now time.Time) bool {
+	if !k.RevokedAt.IsZero() {
+		return false
+	}
+	return k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt)
+}
@@ -15,9 +15,12 @@ const (
 
 type Todo struct {
 	ID          uuid.UUID
+	TenantID    string
 	Title       string
 	Description string
 	Status      Status
+	CreatedBy   uuid.UUID
+	Assignees   []uuid.UUID
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
-}
\ No newline at end of file
+}
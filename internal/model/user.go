@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type User struct {
+	ID        uuid.UUID
+	TenantID  string
+	Email     string
+	Name      string
+	CreatedAt time.Time
+}
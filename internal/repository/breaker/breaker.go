@@ -0,0 +1,247 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+type Config struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// circuit.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before letting probes
+	// through.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of concurrent calls allowed while
+	// half-open.
+	HalfOpenProbes int
+	// MaxInFlight sheds load once this many calls are running. Zero disables
+	// the limit.
+	MaxInFlight int
+}
+
+type state int
+
+const (
+	stateClosed state = iota
+	stateOpen
+	stateHalfOpen
+)
+
+// Repo decorates a TodoRepository with a circuit breaker and a concurrency
+// limit. While open it fails fast with a *repository.UnavailableError.
+type Repo struct {
+	next     repository.TodoRepository
+	cfg      Config
+	inFlight chan struct{}
+	now      func() time.Time
+
+	mu       sync.Mutex
+	state    state
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+func New(next repository.TodoRepository, cfg Config) *Repo {
+	if cfg.FailureThreshold < 1 {
+		cfg.FailureThreshold = 1
+	}
+	if cfg.HalfOpenProbes < 1 {
+		cfg.HalfOpenProbes = 1
+	}
+	r := &Repo{next: next, cfg: cfg, now: time.Now}
+	if cfg.MaxInFlight > 0 {
+		r.inFlight = make(chan struct{}, cfg.MaxInFlight)
+	}
+	return r
+}
+
+func (r *Repo) Create(ctx context.Context, todo model.Todo) error {
+	_, err := call(r, func() (struct{}, error) {
+		return struct{}{}, r.next.Create(ctx, todo)
+	})
+	return err
+}
+
+func (r *Repo) CreateBatch(ctx context.Context, todos []model.Todo) error {
+	_, err := call(r, func() (struct{}, error) {
+		return struct{}{}, r.next.CreateBatch(ctx, todos)
+	})
+	return err
+}
+
+func (r *Repo) Get(ctx context.Context, id uuid.UUID) (model.Todo, error) {
+	return call(r, func() (model.Todo, error) {
+		return r.next.Get(ctx, id)
+	})
+}
+
+func (r *Repo) List(ctx context.Context, filter repository.ListFilter) ([]model.Todo, error) {
+	return call(r, func() ([]model.Todo, error) {
+		return r.next.List(ctx, filter)
+	})
+}
+
+// Export counts as a single call for its whole duration. Errors returned by
+// fn, such as a client going away, are not database failures and leave the
+// breaker alone.
+func (r *Repo) Export(ctx context.Context, filter repository.ListFilter, fn func(model.Todo) error) error {
+	var callerErr error
+	_, err := call(r, func() (struct{}, error) {
+		var fnErr error
+		err := r.next.Export(ctx, filter, func(todo model.Todo) error {
+			fnErr = fn(todo)
+			return fnErr
+		})
+		if fnErr != nil {
+			callerErr = err
+			return struct{}{}, nil
+		}
+		return struct{}{}, err
+	})
+	if callerErr != nil {
+		return callerErr
+	}
+	return err
+}
+
+func (r *Repo) Count(ctx context.Context) (int, error) {
+	return call(r, func() (int, error) {
+		return r.next.Count(ctx)
+	})
+}
+
+func (r *Repo) Update(ctx context.Context, todo model.Todo) error {
+	_, err := call(r, func() (struct{}, error) {
+		return struct{}{}, r.next.Update(ctx, todo)
+	})
+	return err
+}
+
+func (r *Repo) Upsert(ctx context.Context, todo model.Todo) (model.Todo, bool, error) {
+	type result struct {
+		todo    model.Todo
+		created bool
+	}
+	res, err := call(r, func() (result, error) {
+		todo, created, err := r.next.Upsert(ctx, todo)
+		return result{todo: todo, created: created}, err
+	})
+	return res.todo, res.created, err
+}
+
+func (r *Repo) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
+	return call(r, func() (bool, error) {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+func call[T any](r *Repo, fn func() (T, error)) (T, error) {
+	var zero T
+	if r.inFlight != nil {
+		select {
+		case r.inFlight <- struct{}{}:
+			defer func() { <-r.inFlight }()
+		default:
+			return zero, &repository.UnavailableError{Reason: "too many in-flight requests", RetryAfter: time.Second}
+		}
+	}
+
+	probe, err := r.acquire()
+	if err != nil {
+		return zero, err
+	}
+	out, err := fn()
+	r.release(probe, isFailure(err))
+	return out, err
+}
+
+// acquire decides whether a call may proceed and whether it is a half-open
+// probe.
+func (r *Repo) acquire() (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch r.state {
+	case stateOpen:
+		elapsed := r.now().Sub(r.openedAt)
+		if elapsed < r.cfg.OpenTimeout {
+			return false, &repository.UnavailableError{Reason: "circuit breaker open", RetryAfter: r.cfg.OpenTimeout - elapsed}
+		}
+		r.state = stateHalfOpen
+		r.probes = 0
+		fallthrough
+	case stateHalfOpen:
+		if r.probes >= r.cfg.HalfOpenProbes {
+			return false, &repository.UnavailableError{Reason: "circuit breaker half-open", RetryAfter: r.cfg.OpenTimeout}
+		}
+		r.probes++
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func (r *Repo) release(probe, failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if probe {
+		r.probes--
+		if r.state != stateHalfOpen {
+			return
+		}
+		if failed {
+			r.trip()
+			return
+		}
+		r.state = stateClosed
+		r.failures = 0
+		return
+	}
+	if r.state != stateClosed {
+		return
+	}
+	if !failed {
+		r.failures = 0
+		return
+	}
+	r.failures++
+	if r.failures >= r.cfg.FailureThreshold {
+		r.trip()
+	}
+}
+
+func (r *Repo) trip() {
+	r.state = stateOpen
+	r.openedAt = r.now()
+	r.failures = 0
+}
+
+// isFailure reports whether err indicates an unhealthy database rather than
+// a domain outcome such as a missing row or a constraint violation.
+func isFailure(err error) bool {
+	switch {
+	case err == nil,
+		errors.Is(err, context.Canceled),
+		errors.Is(err, tenant.ErrMissing),
+		errors.Is(err, repository.ErrNotFound),
+		errors.Is(err, repository.ErrConflict),
+		errors.Is(err, repository.ErrForeignKeyViolation),
+		errors.Is(err, repository.ErrCheckViolation),
+		errors.Is(err, repository.ErrSerialization),
+		errors.Is(err, repository.ErrQuotaExceeded):
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,56 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/repository/memory"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+type flakyRepo struct {
+	*memory.Repo
+	err error
+}
+
+func (f *flakyRepo) Get(ctx context.Context, id uuid.UUID) (model.Todo, error) {
+	if f.err != nil {
+		return model.Todo{}, f.err
+	}
+	return f.Repo.Get(ctx, id)
+}
+
+func TestBreaker_OpensThenRecoversThroughProbe(t *testing.T) {
+	next := &flakyRepo{Repo: memory.New(), err: repository.ErrConnectionLost}
+	now := time.Now()
+	b := New(next, Config{FailureThreshold: 2, OpenTimeout: time.Minute, HalfOpenProbes: 1})
+	b.now = func() time.Time { return now }
+	ctx := tenant.WithID(context.Background(), "acme")
+
+	for i := 0; i < 2; i++ {
+		if _, err := b.Get(ctx, uuid.New()); !errors.Is(err, repository.ErrConnectionLost) {
+			t.Fatalf("call %d: expected connection lost, got %v", i, err)
+		}
+	}
+
+	_, err := b.Get(ctx, uuid.New())
+	var unavailable *repository.UnavailableError
+	if !errors.As(err, &unavailable) || unavailable.RetryAfter != time.Minute {
+		t.Fatalf("expected open circuit with retry after 1m, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	next.err = nil
+	if _, err := b.Get(ctx, uuid.New()); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected probe to reach repository, got %v", err)
+	}
+	if _, err := b.Get(ctx, uuid.New()); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected closed circuit, got %v", err)
+	}
+}
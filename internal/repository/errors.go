@@ -1,8 +1,42 @@
 package repository
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
-	ErrNotFound = errors.New("not found")
-	ErrConflict = errors.New("conflict")
-)
\ No newline at end of file
+	ErrNotFound            = errors.New("not found")
+	ErrConflict            = errors.New("conflict")
+	ErrForeignKeyViolation = errors.New("foreign key violation")
+	ErrCheckViolation      = errors.New("check violation")
+	ErrSerialization       = errors.New("serialization failure")
+	ErrQueryCanceled       = errors.New("query canceled")
+	ErrConnectionLost      = errors.New("connection lost")
+	ErrUnavailable         = errors.New("repository unavailable")
+
+	// ErrOutcomeUnknown is returned when the connection was lost while a
+	// transaction was committing, so it may or may not have been applied.
+	// It is never retried automatically.
+	ErrOutcomeUnknown = errors.New("transaction outcome unknown")
+
+	// ErrQuotaExceeded is returned by inserts that would take the tenant
+	// over the quota set with WithQuota.
+	ErrQuotaExceeded = errors.New("tenant quota exceeded")
+)
+
+// UnavailableError is returned without touching the database when the
+// repository is shedding load or its circuit breaker is open. It matches
+// ErrUnavailable with errors.Is.
+type UnavailableError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *UnavailableError) Error() string {
+	return "repository unavailable: " + e.Reason
+}
+
+func (e *UnavailableError) Is(target error) bool {
+	return target == ErrUnavailable
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotencyRecord is the stored outcome of a request made with an
+// idempotency key. A record without a Response is a reservation held by a
+// request that is still running.
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string
+	Response    []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// IdempotencyStore persists idempotency records. Expired records count as
+// absent.
+type IdempotencyStore interface {
+	// Reserve stores record, which has no response yet, unless a live record
+	// already exists for its key. It reports whether the record was stored;
+	// if not, it returns the live record instead.
+	Reserve(ctx context.Context, record IdempotencyRecord) (IdempotencyRecord, bool, error)
+	// Complete stores the response of a reserved key and keeps the record
+	// until expiresAt.
+	Complete(ctx context.Context, key string, response []byte, expiresAt time.Time) error
+	// Release deletes a reservation that has no response, so the key can be
+	// used again.
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+func (r *Repo) Grant(ctx context.Context, grant model.Grant) error {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	todo, ok := r.items[grant.TodoID]
+	if !ok || todo.TenantID != tenantID {
+		return repository.ErrNotFound
+	}
+	grant.TenantID = tenantID
+	grants := r.grants[grant.TodoID]
+	for i, existing := range grants {
+		if existing.SubjectType == grant.SubjectType && existing.SubjectID == grant.SubjectID {
+			grant.CreatedAt = existing.CreatedAt
+			grants[i] = grant
+			return nil
+		}
+	}
+	r.grants[grant.TodoID] = append(grants, grant)
+	return nil
+}
+
+func (r *Repo) Revoke(ctx context.Context, todoID uuid.UUID, subjectType model.SubjectType, subjectID string) (bool, error) {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	todo, ok := r.items[todoID]
+	if !ok || todo.TenantID != tenantID {
+		return false, nil
+	}
+	grants := r.grants[todoID]
+	for i, existing := range grants {
+		if existing.SubjectType == subjectType && existing.SubjectID == subjectID {
+			r.grants[todoID] = slices.Delete(grants, i, i+1)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *Repo) Grants(ctx context.Context, todoID uuid.UUID) ([]model.Grant, error) {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	todo, ok := r.items[todoID]
+	if !ok || todo.TenantID != tenantID {
+		return nil, repository.ErrNotFound
+	}
+	return slices.Clone(r.grants[todoID]), nil
+}
+
+// visible must be called with r.mu held.
+func (r *Repo) visible(todo model.Todo, viewer *repository.Viewer) bool {
+	if viewer == nil || todo.CreatedBy == uuid.Nil {
+		return true
+	}
+	if viewer.UserID != uuid.Nil && (todo.CreatedBy == viewer.UserID || slices.Contains(todo.Assignees, viewer.UserID)) {
+		return true
+	}
+	for _, grant := range r.grants[todo.ID] {
+		if grant.AppliesTo(viewer.UserID, viewer.Groups) {
+			return true
+		}
+	}
+	return false
+}
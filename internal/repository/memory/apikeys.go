@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+)
+
+type APIKeyStore struct {
+	mu   sync.RWMutex
+	keys map[uuid.UUID]model.APIKey
+}
+
+func NewAPIKeyStore() *APIKeyStore {
+	return &APIKeyStore{keys: make(map[uuid.UUID]model.APIKey)}
+}
+
+func (s *APIKeyStore) Create(ctx context.Context, key model.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.keys {
+		if existing.ID == key.ID || existing.Prefix == key.Prefix {
+			return repository.ErrConflict
+		}
+	}
+	s.keys[key.ID] = key
+	return nil
+}
+
+func (s *APIKeyStore) Get(ctx context.Context, id uuid.UUID) (model.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[id]
+	if !ok {
+		return model.APIKey{}, repository.ErrNotFound
+	}
+	return key, nil
+}
+
+func (s *APIKeyStore) GetByPrefix(ctx context.Context, prefix string) (model.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, key := range s.keys {
+		if key.Prefix == prefix {
+			return key, nil
+		}
+	}
+	return model.APIKey{}, repository.ErrNotFound
+}
+
+func (s *APIKeyStore) List(ctx context.Context, tenantID string) ([]model.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]model.APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		if tenantID == "" || key.TenantID == tenantID {
+			result = append(result, key)
+		}
+	}
+	slices.SortFunc(result, func(a, b model.APIKey) int {
+		return strings.Compare(a.Prefix, b.Prefix)
+	})
+	return result, nil
+}
+
+func (s *APIKeyStore) Revoke(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[id]
+	if !ok || !key.RevokedAt.IsZero() {
+		return false, nil
+	}
+	key.RevokedAt = at
+	s.keys[id] = key
+	return true, nil
+}
+
+func (s *APIKeyStore) SetExpiry(ctx context.Context, id uuid.UUID, expiresAt time.Time) error {
+	return s.update(id, func(key *model.APIKey) { key.ExpiresAt = expiresAt })
+}
+
+func (s *APIKeyStore) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return s.update(id, func(key *model.APIKey) { key.LastUsedAt = at })
+}
+
+func (s *APIKeyStore) update(id uuid.UUID, fn func(*model.APIKey)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	fn(&key)
+	s.keys[id] = key
+	return nil
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+)
+
+// sweepInterval is how often the store drops expired records. Between
+// sweeps, expired records are replaced when their key is reserved again.
+const sweepInterval = time.Minute
+
+type IdempotencyStore struct {
+	mu        sync.Mutex
+	records   map[string]repository.IdempotencyRecord
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+func NewIdempotencyStore() *IdempotencyStore {
+	return &IdempotencyStore{
+		records: make(map[string]repository.IdempotencyRecord),
+		now:     time.Now,
+	}
+}
+
+func (s *IdempotencyStore) Reserve(ctx context.Context, record repository.IdempotencyRecord) (repository.IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+	if existing, ok := s.records[record.Key]; ok && existing.ExpiresAt.After(now) {
+		return existing, false, nil
+	}
+	record.Response = nil
+	s.records[record.Key] = record
+	return record, true, nil
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, response []byte, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[key]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	record.Response = response
+	record.ExpiresAt = expiresAt
+	s.records[key] = record
+	return nil
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[key]; ok && record.Response == nil {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+// sweep drops expired records at most once per sweepInterval, so Reserve
+// stays constant time. It requires s.mu to be held.
+func (s *IdempotencyStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(sweepInterval)
+	for key, record := range s.records {
+		if !record.ExpiresAt.After(now) {
+			delete(s.records, key)
+		}
+	}
+}
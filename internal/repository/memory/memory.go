@@ -2,34 +2,53 @@ package memory
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/google/uuid"
 
 	"github.com/fuzail-ahmed/codex-test/internal/model"
 	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
 )
 
 type Repo struct {
-	mu    sync.RWMutex
-	items map[uuid.UUID]model.Todo
+	mu     sync.RWMutex
+	items  map[uuid.UUID]model.Todo
+	grants map[uuid.UUID][]model.Grant
 }
 
 func New() *Repo {
-	return &Repo{items: make(map[uuid.UUID]model.Todo)}
+	return &Repo{
+		items:  make(map[uuid.UUID]model.Todo),
+		grants: make(map[uuid.UUID][]model.Grant),
+	}
 }
 
 func (r *Repo) Create(ctx context.Context, todo model.Todo) error {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return err
+	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if _, exists := r.items[todo.ID]; exists {
 		return repository.ErrConflict
 	}
+	if err := r.checkQuota(ctx, tenantID, 1); err != nil {
+		return err
+	}
+	todo.TenantID = tenantID
 	r.items[todo.ID] = todo
 	return nil
 }
 
 func (r *Repo) CreateBatch(ctx context.Context, todos []model.Todo) error {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return err
+	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	for _, todo := range todos {
@@ -37,48 +56,181 @@ func (r *Repo) CreateBatch(ctx context.Context, todos []model.Todo) error {
 			return repository.ErrConflict
 		}
 	}
+	if err := r.checkQuota(ctx, tenantID, len(todos)); err != nil {
+		return err
+	}
 	for _, todo := range todos {
+		todo.TenantID = tenantID
 		r.items[todo.ID] = todo
 	}
 	return nil
 }
 
 func (r *Repo) Get(ctx context.Context, id uuid.UUID) (model.Todo, error) {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return model.Todo{}, err
+	}
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	todo, ok := r.items[id]
-	if !ok {
+	if !ok || todo.TenantID != tenantID {
 		return model.Todo{}, repository.ErrNotFound
 	}
 	return todo, nil
 }
 
 func (r *Repo) List(ctx context.Context, filter repository.ListFilter) ([]model.Todo, error) {
+	result, err := r.matching(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	// Same bounds as the Postgres repository.
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	limit = min(limit, 200)
+	offset := min(max(filter.Offset, 0), len(result))
+	return result[offset:min(offset+limit, len(result))], nil
+}
+
+// Export works on a snapshot taken up front; unlike Postgres it holds all
+// matching todos in memory.
+func (r *Repo) Export(ctx context.Context, filter repository.ListFilter, fn func(model.Todo) error) error {
+	result, err := r.matching(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, todo := range result {
+		if err := fn(todo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matching returns the todos visible through filter in List order, newest
+// first, ignoring Limit and Offset.
+func (r *Repo) matching(ctx context.Context, filter repository.ListFilter) ([]model.Todo, error) {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	result := make([]model.Todo, 0, len(r.items))
 	for _, todo := range r.items {
-		result = append(result, todo)
+		if todo.TenantID == tenantID && matches(todo, filter) && r.visible(todo, filter.VisibleTo) {
+			result = append(result, todo)
+		}
 	}
+	slices.SortFunc(result, func(a, b model.Todo) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID.String(), a.ID.String())
+	})
 	return result, nil
 }
 
+func matches(todo model.Todo, filter repository.ListFilter) bool {
+	if filter.CreatedBy != uuid.Nil && todo.CreatedBy != filter.CreatedBy {
+		return false
+	}
+	if filter.AssignedTo != uuid.Nil && !slices.Contains(todo.Assignees, filter.AssignedTo) {
+		return false
+	}
+	if after := filter.After; after != nil {
+		c := todo.CreatedAt.Compare(after.CreatedAt)
+		if c > 0 || c == 0 && todo.ID.String() >= after.ID.String() {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Repo) Count(ctx context.Context) (int, error) {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.count(tenantID), nil
+}
+
+// count requires r.mu to be held.
+func (r *Repo) count(tenantID string) int {
+	count := 0
+	for _, todo := range r.items {
+		if todo.TenantID == tenantID {
+			count++
+		}
+	}
+	return count
+}
+
+// checkQuota fails with ErrQuotaExceeded if adding n todos would take the
+// tenant over the quota in ctx. It requires r.mu to be held for writing, so
+// the check and the insert are atomic.
+func (r *Repo) checkQuota(ctx context.Context, tenantID string, n int) error {
+	if quota := repository.QuotaFromContext(ctx); quota > 0 && r.count(tenantID)+n > quota {
+		return repository.ErrQuotaExceeded
+	}
+	return nil
+}
+
 func (r *Repo) Update(ctx context.Context, todo model.Todo) error {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return err
+	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, ok := r.items[todo.ID]; !ok {
+	existing, ok := r.items[todo.ID]
+	if !ok || existing.TenantID != tenantID {
 		return repository.ErrNotFound
 	}
+	todo.TenantID = tenantID
 	r.items[todo.ID] = todo
 	return nil
 }
 
+func (r *Repo) Upsert(ctx context.Context, todo model.Todo) (model.Todo, bool, error) {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return model.Todo{}, false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.items[todo.ID]
+	if ok {
+		if existing.TenantID != tenantID {
+			return model.Todo{}, false, repository.ErrConflict
+		}
+		todo.CreatedBy = existing.CreatedBy
+		todo.CreatedAt = existing.CreatedAt
+	} else if err := r.checkQuota(ctx, tenantID, 1); err != nil {
+		return model.Todo{}, false, err
+	}
+	todo.TenantID = tenantID
+	r.items[todo.ID] = todo
+	return todo, !ok, nil
+}
+
 func (r *Repo) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return false, err
+	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, ok := r.items[id]; !ok {
+	existing, ok := r.items[id]
+	if !ok || existing.TenantID != tenantID {
 		return false, nil
 	}
 	delete(r.items, id)
+	delete(r.grants, id)
 	return true, nil
 }
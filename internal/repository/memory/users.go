@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+type UserRepo struct {
+	mu    sync.RWMutex
+	users map[uuid.UUID]model.User
+}
+
+func NewUserRepo() *UserRepo {
+	return &UserRepo{users: make(map[uuid.UUID]model.User)}
+}
+
+func (r *UserRepo) Create(ctx context.Context, user model.User) error {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.users[user.ID]; exists {
+		return repository.ErrConflict
+	}
+	for _, existing := range r.users {
+		if existing.TenantID == tenantID && existing.Email == user.Email {
+			return repository.ErrConflict
+		}
+	}
+	user.TenantID = tenantID
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *UserRepo) Get(ctx context.Context, id uuid.UUID) (model.User, error) {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return model.User{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	user, ok := r.users[id]
+	if !ok || user.TenantID != tenantID {
+		return model.User{}, repository.ErrNotFound
+	}
+	return user, nil
+}
+
+func (r *UserRepo) List(ctx context.Context, filter repository.ListFilter) ([]model.User, error) {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]model.User, 0, len(r.users))
+	for _, user := range r.users {
+		if user.TenantID == tenantID {
+			result = append(result, user)
+		}
+	}
+	return result, nil
+}
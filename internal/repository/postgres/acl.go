@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+)
+
+// Grant inserts the grant only if the todo exists in the caller's tenant;
+// otherwise it reports ErrNotFound.
+func (r *Repo) Grant(ctx context.Context, grant model.Grant) error {
+	ctx, cancel := r.opContext(ctx)
+	defer cancel()
+	return r.withTx(ctx, func(tx *sql.Tx, tenantID string) error {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO todo_acl (todo_id, tenant_id, subject_type, subject_id, permission, created_at)
+			SELECT id, tenant_id, $3, $4, $5, $6 FROM todos WHERE id = $1 AND tenant_id = $2
+			ON CONFLICT (todo_id, subject_type, subject_id) DO UPDATE
+			SET permission = EXCLUDED.permission
+		`, grant.TodoID, tenantID, string(grant.SubjectType), grant.SubjectID, string(grant.Permission), grant.CreatedAt)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *Repo) Revoke(ctx context.Context, todoID uuid.UUID, subjectType model.SubjectType, subjectID string) (bool, error) {
+	ctx, cancel := r.opContext(ctx)
+	defer cancel()
+	var deleted bool
+	err := r.withTx(ctx, func(tx *sql.Tx, tenantID string) error {
+		res, err := tx.ExecContext(ctx, `
+			DELETE FROM todo_acl
+			WHERE todo_id = $1 AND tenant_id = $2 AND subject_type = $3 AND subject_id = $4
+		`, todoID, tenantID, string(subjectType), subjectID)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		deleted = rows > 0
+		return nil
+	})
+	return deleted, err
+}
+
+func (r *Repo) Grants(ctx context.Context, todoID uuid.UUID) ([]model.Grant, error) {
+	ctx, cancel := r.opContext(ctx)
+	defer cancel()
+	var result []model.Grant
+	err := r.read(ctx, func(tx *sql.Tx, tenantID string) error {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM todos WHERE id = $1 AND tenant_id = $2)
+		`, todoID, tenantID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return repository.ErrNotFound
+		}
+		rows, err := tx.QueryContext(ctx, `
+			SELECT todo_id, tenant_id, subject_type, subject_id, permission, created_at
+			FROM todo_acl
+			WHERE todo_id = $1 AND tenant_id = $2
+			ORDER BY subject_type, subject_id
+		`, todoID, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		result = []model.Grant{}
+		for rows.Next() {
+			var grant model.Grant
+			var subjectType, permission string
+			if err := rows.Scan(&grant.TodoID, &grant.TenantID, &subjectType, &grant.SubjectID, &permission, &grant.CreatedAt); err != nil {
+				return err
+			}
+			grant.SubjectType = model.SubjectType(subjectType)
+			grant.Permission = model.Permission(permission)
+			result = append(result, grant)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+)
+
+const selectAPIKey = `
+	SELECT id, tenant_id, name, prefix, hash, scopes, expires_at, last_used_at, revoked_at, created_at
+	FROM api_keys`
+
+type APIKeyStore struct {
+	db *sql.DB
+}
+
+func NewAPIKeyStore(db *sql.DB) *APIKeyStore {
+	return &APIKeyStore{db: db}
+}
+
+func (s *APIKeyStore) Create(ctx context.Context, key model.APIKey) error {
+	scopes := key.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO api_keys (id, tenant_id, name, prefix, hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, key.ID, key.TenantID, key.Name, key.Prefix, key.Hash, scopes, nullTime(key.ExpiresAt), key.CreatedAt)
+	return mapError(err)
+}
+
+func (s *APIKeyStore) Get(ctx context.Context, id uuid.UUID) (model.APIKey, error) {
+	return scanAPIKey(s.db.QueryRowContext(ctx, selectAPIKey+` WHERE id = $1`, id))
+}
+
+func (s *APIKeyStore) GetByPrefix(ctx context.Context, prefix string) (model.APIKey, error) {
+	return scanAPIKey(s.db.QueryRowContext(ctx, selectAPIKey+` WHERE prefix = $1`, prefix))
+}
+
+func (s *APIKeyStore) List(ctx context.Context, tenantID string) ([]model.APIKey, error) {
+	rows, err := s.db.QueryContext(ctx, selectAPIKey+`
+		WHERE $1 = '' OR tenant_id = $1
+		ORDER BY tenant_id, created_at
+	`, tenantID)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	result := []model.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, key)
+	}
+	return result, mapError(rows.Err())
+}
+
+func (s *APIKeyStore) Revoke(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE api_keys SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL
+	`, id, at)
+	if err != nil {
+		return false, mapError(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (s *APIKeyStore) SetExpiry(ctx context.Context, id uuid.UUID, expiresAt time.Time) error {
+	return s.exec(ctx, `UPDATE api_keys SET expires_at = $2 WHERE id = $1`, id, expiresAt)
+}
+
+func (s *APIKeyStore) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return s.exec(ctx, `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`, id, at)
+}
+
+func (s *APIKeyStore) exec(ctx context.Context, query string, id uuid.UUID, at time.Time) error {
+	res, err := s.db.ExecContext(ctx, query, id, at)
+	if err != nil {
+		return mapError(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func scanAPIKey(row rowScanner) (model.APIKey, error) {
+	var key model.APIKey
+	var scopes textArray
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(&key.ID, &key.TenantID, &key.Name, &key.Prefix, &key.Hash, &scopes,
+		&expiresAt, &lastUsedAt, &revokedAt, &key.CreatedAt)
+	if err == sql.ErrNoRows {
+		return model.APIKey{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return model.APIKey{}, mapError(err)
+	}
+	key.Scopes = scopes
+	key.ExpiresAt = expiresAt.Time
+	key.LastUsedAt = lastUsedAt.Time
+	key.RevokedAt = revokedAt.Time
+	return key, nil
+}
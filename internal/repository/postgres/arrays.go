@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var typeMap = pgtype.NewMap()
+
+// uuidArray scans a Postgres uuid[] column. database/sql hands arrays over in
+// their text representation, which pgtype decodes.
+type uuidArray []uuid.UUID
+
+func (a *uuidArray) Scan(src any) error {
+	var text []byte
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case string:
+		text = []byte(v)
+	case []byte:
+		text = v
+	default:
+		return fmt.Errorf("cannot scan %T into uuid[]", src)
+	}
+	var ids []uuid.UUID
+	if err := typeMap.Scan(pgtype.UUIDArrayOID, pgtype.TextFormatCode, text, &ids); err != nil {
+		return err
+	}
+	*a = ids
+	return nil
+}
+
+// textArray scans a Postgres text[] column.
+type textArray []string
+
+func (a *textArray) Scan(src any) error {
+	var text []byte
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case string:
+		text = []byte(v)
+	case []byte:
+		text = v
+	default:
+		return fmt.Errorf("cannot scan %T into text[]", src)
+	}
+	var items []string
+	if err := typeMap.Scan(pgtype.TextArrayOID, pgtype.TextFormatCode, text, &items); err != nil {
+		return err
+	}
+	*a = items
+	return nil
+}
+
+// uuidArrayArg returns a non-nil slice so empty lists are stored as '{}'
+// rather than NULL.
+func uuidArrayArg(ids []uuid.UUID) []uuid.UUID {
+	if ids == nil {
+		return []uuid.UUID{}
+	}
+	return ids
+}
+
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+)
+
+// SQLSTATE codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html.
+const (
+	codeUniqueViolation       = "23505"
+	codeForeignKeyViolation   = "23503"
+	codeCheckViolation        = "23514"
+	codeInsufficientPrivilege = "42501"
+	codeSerializationFailure  = "40001"
+	codeDeadlockDetected      = "40P01"
+	codeQueryCanceled         = "57014"
+	codeAdminShutdown         = "57P01"
+	codeCrashShutdown         = "57P02"
+	codeCannotConnectNow      = "57P03"
+	classConnectionException  = "08"
+)
+
+// mapError translates driver errors into repository errors. The original
+// error stays in the chain so callers can still inspect the PgError.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if target := classify(err); target != nil && !errors.Is(err, target) {
+		return fmt.Errorf("%w: %w", target, err)
+	}
+	return err
+}
+
+// mapCommitError maps an error from COMMIT. If the connection was lost the
+// server may have committed before it went away, so the error becomes
+// ErrOutcomeUnknown rather than a retryable ErrConnectionLost.
+func mapCommitError(err error) error {
+	err = mapError(err)
+	if errors.Is(err, repository.ErrConnectionLost) {
+		return fmt.Errorf("%w: %v", repository.ErrOutcomeUnknown, err)
+	}
+	return err
+}
+
+func classify(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case codeUniqueViolation:
+			return repository.ErrConflict
+		case codeForeignKeyViolation:
+			return repository.ErrForeignKeyViolation
+		case codeCheckViolation:
+			return repository.ErrCheckViolation
+		case codeInsufficientPrivilege:
+			// Raised by row-level security when a write targets a row owned
+			// by another tenant, e.g. an upsert on a foreign ID.
+			return repository.ErrConflict
+		case codeSerializationFailure, codeDeadlockDetected:
+			return repository.ErrSerialization
+		case codeQueryCanceled:
+			return repository.ErrQueryCanceled
+		case codeAdminShutdown, codeCrashShutdown, codeCannotConnectNow:
+			return repository.ErrConnectionLost
+		}
+		if len(pgErr.Code) >= 2 && pgErr.Code[:2] == classConnectionException {
+			return repository.ErrConnectionLost
+		}
+		return nil
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, driver.ErrBadConn),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.As(err, &netErr) && !netErr.Timeout():
+		return repository.ErrConnectionLost
+	}
+	return nil
+}
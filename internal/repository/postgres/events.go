@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/fuzail-ahmed/codex-test/internal/events"
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+)
+
+// eventAppendLock is the advisory lock key that serializes appends, so
+// sequence numbers become visible in order and pollers never skip an event
+// committed behind a higher one.
+const eventAppendLock = 0x746f646f_65767473
+
+// EventLog stores todo change events in todo_events. StartPrune keeps about
+// the newest retain of them.
+type EventLog struct {
+	db     *sql.DB
+	retain int
+}
+
+func NewEventLog(db *sql.DB, retain int) *EventLog {
+	return &EventLog{db: db, retain: retain}
+}
+
+// eventPayload is the stored body of an event.
+type eventPayload struct {
+	Todo   model.Todo
+	Grants []model.Grant
+}
+
+func (l *EventLog) Append(ctx context.Context, batch []events.Event) error {
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return mapError(err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, int64(eventAppendLock)); err != nil {
+		return mapError(err)
+	}
+	for _, e := range batch {
+		payload, err := json.Marshal(eventPayload{Todo: e.Todo, Grants: e.Grants})
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO todo_events (tenant_id, type, payload, occurred_at) VALUES ($1, $2, $3, $4)
+		`, e.Todo.TenantID, string(e.Type), string(payload), e.Time); err != nil {
+			return mapError(err)
+		}
+	}
+	return mapCommitError(tx.Commit())
+}
+
+// StartPrune deletes all but about the newest retain events every interval
+// until ctx is done. It runs outside the append lock, so appends never wait
+// for it.
+func (l *EventLog) StartPrune(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := l.prune(ctx); err != nil && ctx.Err() == nil {
+					log.Printf("event log prune failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (l *EventLog) prune(ctx context.Context) error {
+	_, err := l.db.ExecContext(ctx, `
+		DELETE FROM todo_events WHERE seq <= (SELECT max(seq) FROM todo_events) - $1
+	`, l.retain)
+	return mapError(err)
+}
+
+func (l *EventLog) Read(ctx context.Context, after uint64, limit int) ([]events.Event, error) {
+	return l.query(ctx, `
+		SELECT seq, type, payload, occurred_at FROM todo_events
+		WHERE seq > $1 ORDER BY seq LIMIT $2
+	`, int64(after), limit)
+}
+
+func (l *EventLog) Tail(ctx context.Context, n int) ([]events.Event, error) {
+	return l.query(ctx, `
+		SELECT seq, type, payload, occurred_at FROM (
+			SELECT seq, type, payload, occurred_at FROM todo_events ORDER BY seq DESC LIMIT $1
+		) tail ORDER BY seq
+	`, n)
+}
+
+func (l *EventLog) query(ctx context.Context, query string, args ...any) ([]events.Event, error) {
+	rows, err := l.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+	var out []events.Event
+	for rows.Next() {
+		var (
+			e       events.Event
+			seq     int64
+			typ     string
+			payload []byte
+		)
+		if err := rows.Scan(&seq, &typ, &payload, &e.Time); err != nil {
+			return nil, mapError(err)
+		}
+		var body eventPayload
+		if err := json.Unmarshal(payload, &body); err != nil {
+			return nil, err
+		}
+		e.Seq, e.Type, e.Todo, e.Grants = uint64(seq), events.Type(typ), body.Todo, body.Grants
+		out = append(out, e)
+	}
+	return out, mapError(rows.Err())
+}
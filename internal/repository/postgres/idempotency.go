@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+)
+
+type IdempotencyStore struct {
+	db *sql.DB
+}
+
+func NewIdempotencyStore(db *sql.DB) *IdempotencyStore {
+	return &IdempotencyStore{db: db}
+}
+
+// Reserve inserts a pending record, replacing an expired record with the
+// same key. The insert and the conflict check are a single statement, so
+// exactly one of several concurrent callers wins a key.
+func (s *IdempotencyStore) Reserve(ctx context.Context, record repository.IdempotencyRecord) (repository.IdempotencyRecord, bool, error) {
+	for {
+		res, err := s.db.ExecContext(ctx, `
+			INSERT INTO idempotency_keys (key, fingerprint, response, created_at, expires_at)
+			VALUES ($1, $2, NULL, $3, $4)
+			ON CONFLICT (key) DO UPDATE
+			SET fingerprint = EXCLUDED.fingerprint, response = NULL,
+				created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+			WHERE idempotency_keys.expires_at <= now()
+		`, record.Key, record.Fingerprint, record.CreatedAt, record.ExpiresAt)
+		if err != nil {
+			return repository.IdempotencyRecord{}, false, mapError(err)
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return repository.IdempotencyRecord{}, false, err
+		}
+		if rows > 0 {
+			record.Response = nil
+			return record, true, nil
+		}
+
+		existing, err := s.get(ctx, record.Key)
+		if err == nil {
+			return existing, false, nil
+		}
+		// The live record was released or expired in between; try again.
+		if err != repository.ErrNotFound {
+			return repository.IdempotencyRecord{}, false, err
+		}
+	}
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, response []byte, expiresAt time.Time) error {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE idempotency_keys SET response = $2, expires_at = $3 WHERE key = $1
+	`, key, response, expiresAt)
+	if err != nil {
+		return mapError(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key = $1 AND response IS NULL`, key)
+	return mapError(err)
+}
+
+// StartSweep deletes expired records every interval until ctx is done.
+// Reserve only replaces an expired record when its key is reused, so
+// without the sweep the table would keep every key ever seen.
+func (s *IdempotencyStore) StartSweep(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.sweep(ctx); err != nil && ctx.Err() == nil {
+					log.Printf("idempotency sweep failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (s *IdempotencyStore) sweep(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= now()`)
+	return mapError(err)
+}
+
+func (s *IdempotencyStore) get(ctx context.Context, key string) (repository.IdempotencyRecord, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT key, fingerprint, response, created_at, expires_at
+		FROM idempotency_keys
+		WHERE key = $1 AND expires_at > now()
+	`, key)
+
+	var record repository.IdempotencyRecord
+	if err := row.Scan(&record.Key, &record.Fingerprint, &record.Response, &record.CreatedAt, &record.ExpiresAt); err != nil {
+		if err == sql.ErrNoRows {
+			return repository.IdempotencyRecord{}, repository.ErrNotFound
+		}
+		return repository.IdempotencyRecord{}, mapError(err)
+	}
+	return record, nil
+}
@@ -5,73 +5,224 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 
 	"github.com/fuzail-ahmed/codex-test/internal/model"
 	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
 )
 
+const (
+	// copyThreshold is the batch size at which CreateBatch switches from
+	// multi-row INSERT statements to the COPY protocol.
+	copyThreshold = 1000
+	// insertChunkSize keeps each multi-row INSERT well below Postgres's
+	// 65535 bind parameter limit (9 parameters per row).
+	insertChunkSize = 500
+	// exportFetchSize is how many rows Export fetches from its cursor at a
+	// time.
+	exportFetchSize = 500
+)
+
+var todoColumns = []string{"id", "tenant_id", "title", "description", "status", "created_by", "assignees", "created_at", "updated_at"}
+
+const selectTodo = `SELECT id, tenant_id, title, description, status, created_by, assignees, created_at, updated_at FROM todos`
+
 type Repo struct {
-	db *sql.DB
+	db        *sql.DB
+	isolation sql.IsolationLevel
+	retry     RetryPolicy
+
+	replicas *replicaSet
+	pins     *writePins
+
+	queryTimeout time.Duration
 }
 
-func New(db *sql.DB) *Repo {
-	return &Repo{db: db}
+type Option func(*Repo)
+
+// WithIsolationLevel sets the isolation level used by every write transaction.
+func WithIsolationLevel(level sql.IsolationLevel) Option {
+	return func(r *Repo) {
+		r.isolation = level
+	}
 }
 
-func (r *Repo) Create(ctx context.Context, todo model.Todo) error {
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO todos (id, title, description, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`, todo.ID, todo.Title, todo.Description, string(todo.Status), todo.CreatedAt, todo.UpdatedAt)
-	if err != nil {
-		if isUniqueViolation(err) {
-			return repository.ErrConflict
+// WithRetryPolicy controls how write transactions are retried after
+// serialization failures, deadlocks and dropped connections.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(r *Repo) {
+		r.retry = policy
+	}
+}
+
+// WithReplicas sends Get and List queries to the given read replicas.
+func WithReplicas(replicas ...*sql.DB) Option {
+	return func(r *Repo) {
+		if len(replicas) > 0 {
+			r.replicas = newReplicaSet(replicas)
 		}
-		return err
 	}
-	return nil
+}
+
+// WithReadYourWrites pins a caller's reads to the primary for window after
+// each of their writes, so they observe their own changes despite
+// replication lag. Other callers keep reading from the replicas.
+func WithReadYourWrites(window time.Duration) Option {
+	return func(r *Repo) {
+		r.pins = nil
+		if window > 0 {
+			r.pins = newWritePins(window)
+		}
+	}
+}
+
+// WithQueryTimeout bounds every single-row operation and read, including its
+// retries. Batch inserts are only bounded by the caller's context and the
+// server-side statement_timeout.
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(r *Repo) {
+		r.queryTimeout = timeout
+	}
+}
+
+func New(db *sql.DB, opts ...Option) *Repo {
+	r := &Repo{db: db, retry: DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// PoolStats reports connection pool statistics for the primary and every
+// replica, keyed by pool name.
+func (r *Repo) PoolStats() map[string]sql.DBStats {
+	stats := map[string]sql.DBStats{"primary": r.db.Stats()}
+	if r.replicas != nil {
+		for i, db := range r.replicas.dbs {
+			stats[fmt.Sprintf("replica_%d", i)] = db.Stats()
+		}
+	}
+	return stats
+}
+
+func (r *Repo) opContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.queryTimeout)
+}
+
+func (r *Repo) Create(ctx context.Context, todo model.Todo) error {
+	ctx, cancel := r.opContext(ctx)
+	defer cancel()
+	return r.withTx(ctx, func(tx *sql.Tx, tenantID string) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO todos (id, tenant_id, title, description, status, created_by, assignees, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, todoArgs(tenantID, todo)...)
+		if err != nil {
+			return err
+		}
+		return checkQuota(ctx, sqlTx{tx}, tenantID)
+	})
 }
 
 func (r *Repo) CreateBatch(ctx context.Context, todos []model.Todo) error {
 	if len(todos) == 0 {
 		return nil
 	}
-	return withTx(ctx, r.db, func(tx *sql.Tx) error {
-		query, args := buildBatchInsert(todos)
-		_, err := tx.ExecContext(ctx, query, args...)
-		if err != nil {
-			if isUniqueViolation(err) {
-				return repository.ErrConflict
+	if len(todos) >= copyThreshold {
+		return r.copyBatch(ctx, todos)
+	}
+	return r.withTx(ctx, func(tx *sql.Tx, tenantID string) error {
+		for start := 0; start < len(todos); start += insertChunkSize {
+			end := min(start+insertChunkSize, len(todos))
+			query, args := buildBatchInsert(tenantID, todos[start:end])
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return err
 			}
+		}
+		return checkQuota(ctx, sqlTx{tx}, tenantID)
+	})
+}
+
+// copyBatch streams todos into the table with COPY inside a single pgx
+// transaction, so the batch is still committed all-or-nothing.
+func (r *Repo) copyBatch(ctx context.Context, todos []model.Todo) error {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	err = r.retry.run(ctx, func() error {
+		return r.copyBatchOnce(ctx, tenantID, todos)
+	})
+	if err == nil {
+		r.pins.mark(ctx, tenantID)
+	}
+	return err
+}
+
+func (r *Repo) copyBatchOnce(ctx context.Context, tenantID string, todos []model.Todo) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return mapError(err)
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn any) error {
+		pgxConn := driverConn.(*stdlib.Conn).Conn()
+		tx, err := pgxConn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgxIsolation(r.isolation)})
+		if err != nil {
 			return err
 		}
-		return nil
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+
+		if _, err := tx.Exec(ctx, setTenantSQL, tenantID); err != nil {
+			return err
+		}
+		_, err = tx.CopyFrom(ctx, pgx.Identifier{"todos"}, todoColumns, pgx.CopyFromSlice(len(todos), func(i int) ([]any, error) {
+			return todoArgs(tenantID, todos[i]), nil
+		}))
+		if err != nil {
+			return err
+		}
+		if err := checkQuota(ctx, tx, tenantID); err != nil {
+			return err
+		}
+		return mapCommitError(tx.Commit(ctx))
 	})
+	return mapError(err)
 }
 
 func (r *Repo) Get(ctx context.Context, id uuid.UUID) (model.Todo, error) {
-	row := r.db.QueryRowContext(ctx, `
-		SELECT id, title, description, status, created_at, updated_at
-		FROM todos
-		WHERE id = $1
-	`, id)
-
+	ctx, cancel := r.opContext(ctx)
+	defer cancel()
 	var todo model.Todo
-	var status string
-	if err := row.Scan(&todo.ID, &todo.Title, &todo.Description, &status, &todo.CreatedAt, &todo.UpdatedAt); err != nil {
+	err := r.read(ctx, func(tx *sql.Tx, tenantID string) error {
+		row := tx.QueryRowContext(ctx, selectTodo+` WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+		var err error
+		todo, err = scanTodo(row)
 		if err == sql.ErrNoRows {
-			return model.Todo{}, repository.ErrNotFound
+			return repository.ErrNotFound
 		}
+		return err
+	})
+	if err != nil {
 		return model.Todo{}, err
 	}
-	todo.Status = model.Status(status)
 	return todo, nil
 }
 
 func (r *Repo) List(ctx context.Context, filter repository.ListFilter) ([]model.Todo, error) {
+	ctx, cancel := r.opContext(ctx)
+	defer cancel()
 	limit := filter.Limit
 	offset := filter.Offset
 	if limit <= 0 {
@@ -84,94 +235,323 @@ func (r *Repo) List(ctx context.Context, filter repository.ListFilter) ([]model.
 		offset = 0
 	}
 
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, title, description, status, created_at, updated_at
-		FROM todos
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`, limit, offset)
+	var result []model.Todo
+	err := r.read(ctx, func(tx *sql.Tx, tenantID string) error {
+		where, args := listWhere(tenantID, filter)
+		args = append(args, limit, offset)
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf(`%s
+			WHERE %s
+			ORDER BY created_at DESC, id DESC
+			LIMIT $%d OFFSET $%d
+		`, selectTodo, where, len(args)-1, len(args)), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		result = []model.Todo{}
+		for rows.Next() {
+			todo, err := scanTodo(rows)
+			if err != nil {
+				return err
+			}
+			result = append(result, todo)
+		}
+		return rows.Err()
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return result, nil
+}
 
-	result := []model.Todo{}
-	for rows.Next() {
-		var todo model.Todo
-		var status string
-		if err := rows.Scan(&todo.ID, &todo.Title, &todo.Description, &status, &todo.CreatedAt, &todo.UpdatedAt); err != nil {
-			return nil, err
+// Export reads through a server-side cursor in one read-only transaction,
+// so the rows form a consistent snapshot. It is not bound by the query
+// timeout; a slow fn keeps the transaction open.
+func (r *Repo) Export(ctx context.Context, filter repository.ListFilter, fn func(model.Todo) error) error {
+	started := false
+	deliver := func(todo model.Todo) error {
+		started = true
+		return fn(todo)
+	}
+	return r.read(ctx, func(tx *sql.Tx, tenantID string) error {
+		// read retries on the primary when a replica connection drops;
+		// starting over would deliver rows twice.
+		if started {
+			return repository.ErrConnectionLost
+		}
+		where, args := listWhere(tenantID, filter)
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DECLARE export_todos NO SCROLL CURSOR FOR %s
+			WHERE %s
+			ORDER BY created_at DESC, id DESC
+		`, selectTodo, where), args...); err != nil {
+			return err
+		}
+		for {
+			n, err := fetchTodos(ctx, tx, deliver)
+			if err != nil {
+				return err
+			}
+			if n < exportFetchSize {
+				return nil
+			}
 		}
-		todo.Status = model.Status(status)
-		result = append(result, todo)
+	})
+}
+
+// fetchTodos passes the next rows of the export cursor to fn and reports
+// how many there were.
+func fetchTodos(ctx context.Context, tx *sql.Tx, fn func(model.Todo) error) (int, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`FETCH %d FROM export_todos`, exportFetchSize))
+	if err != nil {
+		return 0, err
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+	defer rows.Close()
+	n := 0
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return n, err
+		}
+		n++
+		if err := fn(todo); err != nil {
+			return n, err
+		}
 	}
-	return result, nil
+	return n, rows.Err()
+}
+
+func (r *Repo) Count(ctx context.Context) (int, error) {
+	ctx, cancel := r.opContext(ctx)
+	defer cancel()
+	var count int
+	err := r.read(ctx, func(tx *sql.Tx, tenantID string) error {
+		return tx.QueryRowContext(ctx, `SELECT count(*) FROM todos WHERE tenant_id = $1`, tenantID).Scan(&count)
+	})
+	return count, err
 }
 
 func (r *Repo) Update(ctx context.Context, todo model.Todo) error {
-	res, err := r.db.ExecContext(ctx, `
-		UPDATE todos
-		SET title = $2, description = $3, status = $4, updated_at = $5
-		WHERE id = $1
-	`, todo.ID, todo.Title, todo.Description, string(todo.Status), todo.UpdatedAt)
+	ctx, cancel := r.opContext(ctx)
+	defer cancel()
+	return r.withTx(ctx, func(tx *sql.Tx, tenantID string) error {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE todos
+			SET title = $3, description = $4, status = $5, assignees = $6, updated_at = $7
+			WHERE id = $1 AND tenant_id = $2
+		`, todo.ID, tenantID, todo.Title, todo.Description, string(todo.Status), uuidArrayArg(todo.Assignees), todo.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// Upsert never touches a row owned by another tenant: the conditional DO
+// UPDATE returns no row in that case, which is reported as ErrConflict.
+func (r *Repo) Upsert(ctx context.Context, todo model.Todo) (model.Todo, bool, error) {
+	ctx, cancel := r.opContext(ctx)
+	defer cancel()
+	var created bool
+	err := r.withTx(ctx, func(tx *sql.Tx, tenantID string) error {
+		todo.TenantID = tenantID
+		row := tx.QueryRowContext(ctx, `
+			INSERT INTO todos (id, tenant_id, title, description, status, created_by, assignees, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (id) DO UPDATE
+			SET title = EXCLUDED.title, description = EXCLUDED.description,
+				status = EXCLUDED.status, assignees = EXCLUDED.assignees, updated_at = EXCLUDED.updated_at
+			WHERE todos.tenant_id = EXCLUDED.tenant_id
+			RETURNING created_by, created_at, (xmax = 0) AS inserted
+		`, todoArgs(tenantID, todo)...)
+		var createdBy uuid.NullUUID
+		err := row.Scan(&createdBy, &todo.CreatedAt, &created)
+		todo.CreatedBy = createdBy.UUID
+		if err == sql.ErrNoRows {
+			return repository.ErrConflict
+		}
+		if err != nil || !created {
+			return err
+		}
+		return checkQuota(ctx, sqlTx{tx}, tenantID)
+	})
 	if err != nil {
-		return err
+		return model.Todo{}, false, err
 	}
-	rows, err := res.RowsAffected()
+	return todo, created, nil
+}
+
+func (r *Repo) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
+	ctx, cancel := r.opContext(ctx)
+	defer cancel()
+	var deleted bool
+	err := r.withTx(ctx, func(tx *sql.Tx, tenantID string) error {
+		res, err := tx.ExecContext(ctx, `DELETE FROM todos WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		deleted = rows > 0
+		return nil
+	})
+	return deleted, err
+}
+
+// withTx runs fn in a tenant-scoped transaction at the configured isolation
+// level and retries the whole transaction on transient failures.
+func (r *Repo) withTx(ctx context.Context, fn func(tx *sql.Tx, tenantID string) error) error {
+	tenantID, err := tenant.FromContext(ctx)
 	if err != nil {
 		return err
 	}
-	if rows == 0 {
-		return repository.ErrNotFound
+	err = r.retry.run(ctx, func() error {
+		return runTx(ctx, r.db, &sql.TxOptions{Isolation: r.isolation}, tenantID, fn)
+	})
+	if err == nil {
+		r.pins.mark(ctx, tenantID)
 	}
-	return nil
+	return err
 }
 
-func (r *Repo) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
-	res, err := r.db.ExecContext(ctx, `DELETE FROM todos WHERE id = $1`, id)
-	if err != nil {
-		return false, err
+// selectTodoCountSQL reads the tenant's todo count, which the insert and
+// delete triggers on todos maintain (migrations/007_tenant_todo_counts.sql).
+const selectTodoCountSQL = `SELECT todo_count FROM tenant_todo_counts WHERE tenant_id = $1`
+
+// checkQuota fails with ErrQuotaExceeded when the tenant holds more todos
+// than the quota in ctx. Call it after inserting: the insert trigger locks
+// the tenant's count row until commit, so the count includes every
+// concurrent insert that committed first, and a failure rolls back the
+// insert.
+func checkQuota(ctx context.Context, tx rowQuerier, tenantID string) error {
+	quota := repository.QuotaFromContext(ctx)
+	if quota <= 0 {
+		return nil
 	}
-	rows, err := res.RowsAffected()
-	if err != nil {
-		return false, err
+	var count int
+	if err := tx.QueryRow(ctx, selectTodoCountSQL, tenantID).Scan(&count); err != nil {
+		return err
+	}
+	if count > quota {
+		return repository.ErrQuotaExceeded
 	}
-	return rows > 0, nil
+	return nil
+}
+
+// rowQuerier runs single-row queries. pgx transactions implement it
+// directly; sqlTx adapts database/sql ones, so checkQuota serves both.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, query string, args ...any) pgx.Row
+}
+
+type sqlTx struct{ *sql.Tx }
+
+func (tx sqlTx) QueryRow(ctx context.Context, query string, args ...any) pgx.Row {
+	return tx.QueryRowContext(ctx, query, args...)
 }
 
-func withTx(ctx context.Context, db *sql.DB, fn func(*sql.Tx) error) error {
-	tx, err := db.BeginTx(ctx, nil)
+// setTenantSQL scopes the row-level security policies on todos to a tenant
+// for the rest of the current transaction.
+const setTenantSQL = `SELECT set_config('app.tenant_id', $1, true)`
+
+func runTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, tenantID string, fn func(*sql.Tx, string) error) error {
+	tx, err := db.BeginTx(ctx, opts)
 	if err != nil {
-		return err
+		return mapError(err)
 	}
 	defer func() {
 		_ = tx.Rollback()
 	}()
 
-	if err := fn(tx); err != nil {
-		return err
+	if _, err := tx.ExecContext(ctx, setTenantSQL, tenantID); err != nil {
+		return mapError(err)
+	}
+	if err := fn(tx, tenantID); err != nil {
+		return mapError(err)
+	}
+	return mapCommitError(tx.Commit())
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTodo(row rowScanner) (model.Todo, error) {
+	var todo model.Todo
+	var status string
+	var createdBy uuid.NullUUID
+	var assignees uuidArray
+	if err := row.Scan(&todo.ID, &todo.TenantID, &todo.Title, &todo.Description, &status, &createdBy, &assignees, &todo.CreatedAt, &todo.UpdatedAt); err != nil {
+		return model.Todo{}, err
+	}
+	todo.Status = model.Status(status)
+	todo.CreatedBy = createdBy.UUID
+	todo.Assignees = assignees
+	return todo, nil
+}
+
+// todoArgs returns the values for todoColumns in order.
+func todoArgs(tenantID string, todo model.Todo) []any {
+	return []any{
+		todo.ID, tenantID, todo.Title, todo.Description, string(todo.Status),
+		nullUUID(todo.CreatedBy), uuidArrayArg(todo.Assignees), todo.CreatedAt, todo.UpdatedAt,
 	}
-	return tx.Commit()
 }
 
-func buildBatchInsert(todos []model.Todo) (string, []any) {
+// listWhere builds the WHERE clause and arguments shared by list queries.
+func listWhere(tenantID string, filter repository.ListFilter) (string, []any) {
+	conditions := []string{"tenant_id = $1"}
+	args := []any{tenantID}
+	if filter.CreatedBy != uuid.Nil {
+		args = append(args, filter.CreatedBy)
+		conditions = append(conditions, fmt.Sprintf("created_by = $%d", len(args)))
+	}
+	if filter.AssignedTo != uuid.Nil {
+		args = append(args, filter.AssignedTo)
+		conditions = append(conditions, fmt.Sprintf("$%d = ANY(assignees)", len(args)))
+	}
+	if after := filter.After; after != nil {
+		args = append(args, after.CreatedAt, after.ID)
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	if viewer := filter.VisibleTo; viewer != nil {
+		args = append(args, viewer.UserID, viewer.Groups)
+		user, groups := len(args)-1, len(args)
+		conditions = append(conditions, fmt.Sprintf(`(created_by IS NULL OR created_by = $%[1]d OR $%[1]d = ANY(assignees)
+			OR EXISTS (SELECT 1 FROM todo_acl a WHERE a.todo_id = todos.id AND a.tenant_id = todos.tenant_id
+				AND ((a.subject_type = 'user' AND a.subject_id = $%[1]d::text)
+					OR (a.subject_type = 'group' AND a.subject_id = ANY($%[2]d::text[])))))`, user, groups))
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+func buildBatchInsert(tenantID string, todos []model.Todo) (string, []any) {
 	var sb strings.Builder
-	args := make([]any, 0, len(todos)*6)
-	fmt.Fprint(&sb, "INSERT INTO todos (id, title, description, status, created_at, updated_at) VALUES ")
+	cols := len(todoColumns)
+	args := make([]any, 0, len(todos)*cols)
+	fmt.Fprintf(&sb, "INSERT INTO todos (%s) VALUES ", strings.Join(todoColumns, ", "))
 	for i, todo := range todos {
 		if i > 0 {
 			sb.WriteString(",")
 		}
-		idx := i*6 + 1
-		sb.WriteString(fmt.Sprintf("($%d,$%d,$%d,$%d,$%d,$%d)", idx, idx+1, idx+2, idx+3, idx+4, idx+5))
-		args = append(args, todo.ID, todo.Title, todo.Description, string(todo.Status), todo.CreatedAt, todo.UpdatedAt)
+		sb.WriteString("(")
+		for c := 0; c < cols; c++ {
+			if c > 0 {
+				sb.WriteString(",")
+			}
+			fmt.Fprintf(&sb, "$%d", i*cols+c+1)
+		}
+		sb.WriteString(")")
+		args = append(args, todoArgs(tenantID, todo)...)
 	}
 	return sb.String(), args
 }
-
-func isUniqueViolation(err error) bool {
-	return strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint")
-}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+// replicaSet round-robins reads over the replicas that passed their last
+// health check.
+type replicaSet struct {
+	dbs     []*sql.DB
+	healthy []atomic.Bool
+	next    atomic.Uint64
+}
+
+func newReplicaSet(dbs []*sql.DB) *replicaSet {
+	s := &replicaSet{dbs: dbs, healthy: make([]atomic.Bool, len(dbs))}
+	for i := range s.healthy {
+		s.healthy[i].Store(true)
+	}
+	return s
+}
+
+// pick returns a healthy replica and its index, or -1 when none is healthy.
+func (s *replicaSet) pick() (*sql.DB, int) {
+	n := len(s.dbs)
+	start := int(s.next.Add(1) % uint64(n))
+	for i := 0; i < n; i++ {
+		idx := (start + i) % n
+		if s.healthy[idx].Load() {
+			return s.dbs[idx], idx
+		}
+	}
+	return nil, -1
+}
+
+// check pings every replica concurrently, so one slow replica cannot delay
+// the verdict on the others past the next tick.
+func (s *replicaSet) check(ctx context.Context, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for i, db := range s.dbs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pingCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			s.healthy[i].Store(db.PingContext(pingCtx) == nil)
+		}()
+	}
+	wg.Wait()
+}
+
+// pingTimeout leaves headroom within interval so a check finishes before
+// the next one is due.
+func pingTimeout(interval time.Duration) time.Duration {
+	return interval / 2
+}
+
+// StartReplicaHealthChecks pings every replica on interval until ctx is done.
+// Unhealthy replicas are skipped and reads fall back to the primary when no
+// replica is available.
+func (r *Repo) StartReplicaHealthChecks(ctx context.Context, interval time.Duration) {
+	if r.replicas == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.replicas.check(ctx, pingTimeout(interval))
+			}
+		}
+	}()
+}
+
+// read runs fn in a tenant-scoped read-only transaction on a replica when
+// one is usable, and falls back to the primary if there is none or the
+// replica connection fails.
+func (r *Repo) read(ctx context.Context, fn func(tx *sql.Tx, tenantID string) error) error {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	opts := &sql.TxOptions{ReadOnly: true}
+	if r.replicas == nil || r.pins.pinned(ctx, tenantID) {
+		return runTx(ctx, r.db, opts, tenantID, fn)
+	}
+	db, idx := r.replicas.pick()
+	if db == nil {
+		return runTx(ctx, r.db, opts, tenantID, fn)
+	}
+	err = runTx(ctx, db, opts, tenantID, fn)
+	if errors.Is(err, repository.ErrConnectionLost) {
+		r.replicas.healthy[idx].Store(false)
+		return runTx(ctx, r.db, opts, tenantID, fn)
+	}
+	return err
+}
+
+// writePins remembers, per caller, until when reads must go to the
+// primary. A caller is the tenant and user the write was made for, so one
+// caller's writes do not move everyone else's reads off the replicas.
+type writePins struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	until     map[string]time.Time
+	nextSweep time.Time
+}
+
+func newWritePins(window time.Duration) *writePins {
+	return &writePins{window: window, until: make(map[string]time.Time)}
+}
+
+func pinKey(ctx context.Context, tenantID string) string {
+	return tenantID + "/" + auth.UserID(ctx).String()
+}
+
+// mark pins the caller's reads to the primary for the window. It is a
+// no-op when read-your-writes is disabled.
+func (p *writePins) mark(ctx context.Context, tenantID string) {
+	if p == nil {
+		return
+	}
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sweep(now)
+	p.until[pinKey(ctx, tenantID)] = now.Add(p.window)
+}
+
+// pinned reports whether the caller wrote within the window.
+func (p *writePins) pinned(ctx context.Context, tenantID string) bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	until, ok := p.until[pinKey(ctx, tenantID)]
+	return ok && time.Now().Before(until)
+}
+
+// sweep drops expired pins at most once per window, so the map only holds
+// callers that wrote recently.
+func (p *writePins) sweep(now time.Time) {
+	if now.Before(p.nextSweep) {
+		return
+	}
+	for key, until := range p.until {
+		if !now.Before(until) {
+			delete(p.until, key)
+		}
+	}
+	p.nextSweep = now.Add(p.window)
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"expvar"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+)
+
+// retryMetrics counts transaction retries by reason and is published through
+// expvar as "postgres_tx_retries".
+var retryMetrics = expvar.NewMap("postgres_tx_retries")
+
+// RetryPolicy bounds automatic transaction retries. Delays grow exponentially
+// from BaseDelay up to MaxDelay with jitter, and a retry is never started if
+// it would sleep past the context deadline.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   25 * time.Millisecond,
+	MaxDelay:    time.Second,
+}
+
+func (p RetryPolicy) run(ctx context.Context, op func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := op()
+		reason := retryReason(err)
+		if reason == "" {
+			return err
+		}
+		if attempt >= p.MaxAttempts {
+			retryMetrics.Add("exhausted", 1)
+			return err
+		}
+
+		delay := p.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			retryMetrics.Add("exhausted", 1)
+			return err
+		}
+		retryMetrics.Add(reason, 1)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// retryReason reports why err is worth retrying, or "" if it is not. A
+// lost connection is retried only when it happened before COMMIT; runTx
+// reports one during COMMIT as ErrOutcomeUnknown.
+func retryReason(err error) string {
+	switch {
+	case err == nil, errors.Is(err, repository.ErrOutcomeUnknown):
+		return ""
+	case errors.Is(err, repository.ErrSerialization):
+		return "serialization"
+	case errors.Is(err, repository.ErrConnectionLost):
+		return "connection_lost"
+	default:
+		return ""
+	}
+}
+
+// ParseIsolationLevel accepts read_committed, repeatable_read or serializable.
+func ParseIsolationLevel(value string) (sql.IsolationLevel, error) {
+	switch value {
+	case "", "read_committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable_read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	default:
+		return sql.LevelDefault, fmt.Errorf("unknown isolation level %q", value)
+	}
+}
+
+func pgxIsolation(level sql.IsolationLevel) pgx.TxIsoLevel {
+	switch level {
+	case sql.LevelReadCommitted:
+		return pgx.ReadCommitted
+	case sql.LevelRepeatableRead:
+		return pgx.RepeatableRead
+	case sql.LevelSerializable:
+		return pgx.Serializable
+	default:
+		return ""
+	}
+}
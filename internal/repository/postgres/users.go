@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+type UserRepo struct {
+	db *sql.DB
+}
+
+func NewUserRepo(db *sql.DB) *UserRepo {
+	return &UserRepo{db: db}
+}
+
+func (r *UserRepo) Create(ctx context.Context, user model.User) error {
+	return r.withTx(ctx, func(tx *sql.Tx, tenantID string) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO users (id, tenant_id, email, name, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, user.ID, tenantID, user.Email, user.Name, user.CreatedAt)
+		return err
+	})
+}
+
+func (r *UserRepo) Get(ctx context.Context, id uuid.UUID) (model.User, error) {
+	var user model.User
+	err := r.withTx(ctx, func(tx *sql.Tx, tenantID string) error {
+		row := tx.QueryRowContext(ctx, `
+			SELECT id, tenant_id, email, name, created_at
+			FROM users
+			WHERE id = $1 AND tenant_id = $2
+		`, id, tenantID)
+		err := row.Scan(&user.ID, &user.TenantID, &user.Email, &user.Name, &user.CreatedAt)
+		if err == sql.ErrNoRows {
+			return repository.ErrNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+func (r *UserRepo) List(ctx context.Context, filter repository.ListFilter) ([]model.User, error) {
+	limit := filter.Limit
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	offset := max(filter.Offset, 0)
+
+	var result []model.User
+	err := r.withTx(ctx, func(tx *sql.Tx, tenantID string) error {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT id, tenant_id, email, name, created_at
+			FROM users
+			WHERE tenant_id = $1
+			ORDER BY email
+			LIMIT $2 OFFSET $3
+		`, tenantID, limit, offset)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		result = []model.User{}
+		for rows.Next() {
+			var user model.User
+			if err := rows.Scan(&user.ID, &user.TenantID, &user.Email, &user.Name, &user.CreatedAt); err != nil {
+				return err
+			}
+			result = append(result, user)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *UserRepo) withTx(ctx context.Context, fn func(tx *sql.Tx, tenantID string) error) error {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return runTx(ctx, r.db, nil, tenantID, fn)
+}
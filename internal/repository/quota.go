@@ -0,0 +1,23 @@
+package repository
+
+import "context"
+
+type quotaContextKey struct{}
+
+// WithQuota limits how many todos the tenant in ctx may hold after an
+// insert made with ctx. Create, CreateBatch and Upsert check the limit
+// atomically with the insert and fail with ErrQuotaExceeded, inserting
+// nothing, when it would be exceeded. A quota of zero or less means
+// unlimited.
+func WithQuota(ctx context.Context, quota int) context.Context {
+	if quota <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, quotaContextKey{}, quota)
+}
+
+// QuotaFromContext returns the quota set with WithQuota, or zero.
+func QuotaFromContext(ctx context.Context) int {
+	quota, _ := ctx.Value(quotaContextKey{}).(int)
+	return quota
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -11,13 +12,80 @@ import (
 type ListFilter struct {
 	Limit  int
 	Offset int
+	// After, when set, starts the page right after this position, so pages
+	// stay stable while todos are inserted or deleted. Use it instead of
+	// Offset.
+	After *Cursor
+	// CreatedBy and AssignedTo restrict results when not uuid.Nil.
+	CreatedBy  uuid.UUID
+	AssignedTo uuid.UUID
+	// VisibleTo restricts results to todos the viewer can see; nil means no
+	// restriction.
+	VisibleTo *Viewer
 }
 
+// Cursor is a position in list order, newest first: the todos after it were
+// created earlier, or at the same time with a smaller ID.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Viewer is a caller whose access List enforces. A todo is visible when it
+// has no owner, was created by or assigned to UserID, or has a grant for
+// UserID or one of Groups. The zero Viewer sees only unowned todos.
+type Viewer struct {
+	UserID uuid.UUID
+	Groups []string
+}
+
+// TodoRepository implementations scope every call to the tenant carried in
+// ctx (see package tenant) and fail with tenant.ErrMissing without one.
 type TodoRepository interface {
 	Create(ctx context.Context, todo model.Todo) error
 	CreateBatch(ctx context.Context, todos []model.Todo) error
 	Get(ctx context.Context, id uuid.UUID) (model.Todo, error)
 	List(ctx context.Context, filter ListFilter) ([]model.Todo, error)
+	// Export calls fn for every todo matching filter in List order, ignoring
+	// Limit and Offset, without holding the results in memory. An error from
+	// fn stops the export and is returned.
+	Export(ctx context.Context, filter ListFilter, fn func(model.Todo) error) error
+	Count(ctx context.Context) (int, error)
 	Update(ctx context.Context, todo model.Todo) error
+	// Upsert creates the todo or replaces an existing one with the same ID,
+	// keeping the original CreatedAt. It reports whether a row was created.
+	Upsert(ctx context.Context, todo model.Todo) (model.Todo, bool, error)
 	Delete(ctx context.Context, id uuid.UUID) (bool, error)
 }
+
+// UserRepository stores users; like TodoRepository it is scoped to the
+// tenant in ctx.
+type UserRepository interface {
+	Create(ctx context.Context, user model.User) error
+	Get(ctx context.Context, id uuid.UUID) (model.User, error)
+	List(ctx context.Context, filter ListFilter) ([]model.User, error)
+}
+
+// ACLRepository stores per-todo grants, scoped to the tenant in ctx. Grants
+// are removed together with their todo.
+type ACLRepository interface {
+	// Grant creates the grant or replaces the permission of an existing one
+	// for the same subject.
+	Grant(ctx context.Context, grant model.Grant) error
+	Revoke(ctx context.Context, todoID uuid.UUID, subjectType model.SubjectType, subjectID string) (bool, error)
+	Grants(ctx context.Context, todoID uuid.UUID) ([]model.Grant, error)
+}
+
+// APIKeyStore persists API keys. Unlike the other repositories it is not
+// scoped to a tenant: keys are looked up by prefix before the tenant is known.
+type APIKeyStore interface {
+	Create(ctx context.Context, key model.APIKey) error
+	Get(ctx context.Context, id uuid.UUID) (model.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (model.APIKey, error)
+	// List returns the keys of one tenant, or of every tenant when tenantID
+	// is empty.
+	List(ctx context.Context, tenantID string) ([]model.APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+	SetExpiry(ctx context.Context, id uuid.UUID, expiresAt time.Time) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+}
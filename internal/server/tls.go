@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile enables client certificate verification against the CAs
+	// it contains.
+	ClientCAFile string
+	// RequireClientCert rejects clients without a valid certificate. When
+	// false, certificates are verified if presented but optional.
+	RequireClientCert bool
+}
+
+// CertReloader serves certificates and client CAs from files and reloads
+// them when they change on disk, so rotated certificates take effect without
+// a restart.
+type CertReloader struct {
+	cfg      TLSConfig
+	cert     atomic.Pointer[tls.Certificate]
+	clientCA atomic.Pointer[x509.CertPool]
+	modTimes map[string]time.Time
+}
+
+func NewCertReloader(cfg TLSConfig) (*CertReloader, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("tls: certificate and key files are required")
+	}
+	if cfg.RequireClientCert && cfg.ClientCAFile == "" {
+		return nil, errors.New("tls: requiring client certificates needs a client CA file")
+	}
+	r := &CertReloader{cfg: cfg}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Start checks the files every interval until ctx is done. A failed reload
+// is logged and the previous certificates stay in use.
+func (r *CertReloader) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !r.changed() {
+					continue
+				}
+				if err := r.reload(); err != nil {
+					log.Printf("tls reload failed, keeping previous certificates: %v", err)
+					continue
+				}
+				log.Printf("tls certificates reloaded")
+			}
+		}
+	}()
+}
+
+// Config returns a server TLS config that always uses the latest
+// certificates. nextProtos is advertised through ALPN.
+func (r *CertReloader) Config(nextProtos ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   nextProtos,
+				Certificates: []tls.Certificate{*r.cert.Load()},
+			}
+			if pool := r.clientCA.Load(); pool != nil {
+				cfg.ClientCAs = pool
+				cfg.ClientAuth = tls.VerifyClientCertIfGiven
+				if r.cfg.RequireClientCert {
+					cfg.ClientAuth = tls.RequireAndVerifyClientCert
+				}
+			}
+			return cfg, nil
+		},
+	}
+}
+
+func (r *CertReloader) reload() error {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("tls: load key pair: %w", err)
+	}
+	var pool *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("tls: read client CA: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("tls: no certificates in %s", r.cfg.ClientCAFile)
+		}
+	}
+	r.cert.Store(&cert)
+	r.clientCA.Store(pool)
+	r.modTimes = modTimes
+	return nil
+}
+
+func (r *CertReloader) changed() bool {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return false
+	}
+	for file, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *CertReloader) statFiles() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time, 3)
+	for _, file := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+		modTimes[file] = info.ModTime()
+	}
+	return modTimes, nil
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCertReloaderPicksUpRotatedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	writeSelfSigned(t, certFile, keyFile, "first")
+
+	reloader, err := NewCertReloader(TLSConfig{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("new reloader: %v", err)
+	}
+	if got := servedCommonName(t, reloader); got != "first" {
+		t.Fatalf("expected first certificate, got %q", got)
+	}
+
+	writeSelfSigned(t, certFile, keyFile, "second")
+	later := time.Now().Add(time.Minute)
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.Chtimes(file, later, later); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	if !reloader.changed() {
+		t.Fatal("expected rotated files to be detected")
+	}
+	if err := reloader.reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got := servedCommonName(t, reloader); got != "second" {
+		t.Fatalf("expected second certificate, got %q", got)
+	}
+}
+
+func TestNewCertReloaderRequiresClientCAForMutualTLS(t *testing.T) {
+	_, err := NewCertReloader(TLSConfig{CertFile: "a", KeyFile: "b", RequireClientCert: true})
+	if err == nil {
+		t.Fatal("expected error without client CA")
+	}
+}
+
+func servedCommonName(t *testing.T, r *CertReloader) string {
+	t.Helper()
+	cfg, err := r.Config().GetConfigForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("config for client: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func writeSelfSigned(t *testing.T, certFile, keyFile, commonName string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write cert: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/events"
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+)
+
+type ShareTodoInput struct {
+	SubjectType model.SubjectType
+	SubjectID   string
+	Permission  model.Permission
+}
+
+// Share grants a user or group access to a todo, replacing any existing
+// grant for the same subject. Only owners can share.
+func (s *Service) Share(ctx context.Context, todoID uuid.UUID, input ShareTodoInput) (model.Grant, error) {
+	if s.acl == nil {
+		return model.Grant{}, wrapValidation("sharing is not enabled")
+	}
+	subjectID, err := normalizeSubject(input.SubjectType, input.SubjectID)
+	if err != nil {
+		return model.Grant{}, err
+	}
+	if input.SubjectType == model.SubjectUser && s.users != nil {
+		if _, err := s.users.Get(ctx, uuid.MustParse(subjectID)); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return model.Grant{}, invalidField("subject_id", ViolationUnknown, "is not a known user")
+			}
+			return model.Grant{}, err
+		}
+	}
+	if input.Permission.Rank() == 0 {
+		return model.Grant{}, invalidField("permission", ViolationInvalid, "must be viewer, editor or owner")
+	}
+	todo, err := s.getAuthorized(ctx, todoID, model.PermissionOwner)
+	if err != nil {
+		return model.Grant{}, err
+	}
+	grant := model.Grant{
+		TodoID:      todoID,
+		SubjectType: input.SubjectType,
+		SubjectID:   subjectID,
+		Permission:  input.Permission,
+		CreatedAt:   s.now(),
+	}
+	if err := s.acl.Grant(ctx, grant); err != nil {
+		return model.Grant{}, err
+	}
+	grants, err := s.acl.Grants(ctx, todoID)
+	if err != nil {
+		return model.Grant{}, err
+	}
+	if s.events != nil {
+		// Announce the todo to the subjects that can now see it.
+		s.events.Publish(events.Event{Type: events.TypeUpdated, Todo: todo, Grants: grants})
+	}
+	for _, stored := range grants {
+		if stored.SubjectType == grant.SubjectType && stored.SubjectID == grant.SubjectID {
+			return stored, nil
+		}
+	}
+	return model.Grant{}, repository.ErrNotFound
+}
+
+// Unshare removes a grant. Only owners can unshare; the creator's ownership
+// cannot be revoked.
+func (s *Service) Unshare(ctx context.Context, todoID uuid.UUID, subjectType model.SubjectType, subjectID string) (bool, error) {
+	if s.acl == nil {
+		return false, wrapValidation("sharing is not enabled")
+	}
+	subjectID, err := normalizeSubject(subjectType, subjectID)
+	if err != nil {
+		return false, err
+	}
+	if _, err := s.getAuthorized(ctx, todoID, model.PermissionOwner); err != nil {
+		return false, err
+	}
+	return s.acl.Revoke(ctx, todoID, subjectType, subjectID)
+}
+
+// Grants lists who a todo is shared with. Any caller who can view the todo
+// can see its grants.
+func (s *Service) Grants(ctx context.Context, todoID uuid.UUID) ([]model.Grant, error) {
+	if s.acl == nil {
+		return nil, wrapValidation("sharing is not enabled")
+	}
+	if _, err := s.getAuthorized(ctx, todoID, model.PermissionViewer); err != nil {
+		return nil, err
+	}
+	return s.acl.Grants(ctx, todoID)
+}
+
+// getAuthorized loads a todo the caller holds at least required on. Callers
+// who cannot even view it get ErrNotFound so its existence is not revealed.
+func (s *Service) getAuthorized(ctx context.Context, id uuid.UUID, required model.Permission) (model.Todo, error) {
+	todo, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if err := s.authorize(ctx, todo, required); err != nil {
+		return model.Todo{}, err
+	}
+	return todo, nil
+}
+
+func (s *Service) authorize(ctx context.Context, todo model.Todo, required model.Permission) error {
+	if s.acl == nil {
+		return nil
+	}
+	permission, err := s.permission(ctx, todo)
+	if err != nil {
+		return err
+	}
+	if !permission.Includes(model.PermissionViewer) {
+		return repository.ErrNotFound
+	}
+	if !permission.Includes(required) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// permission resolves the caller's effective access: todos without an owner
+// are open to everyone in the tenant, creators own their todos, assignees
+// can edit, and grants add the highest level matching the caller or a group.
+func (s *Service) permission(ctx context.Context, todo model.Todo) (model.Permission, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if todo.CreatedBy == uuid.Nil || (ok && todo.CreatedBy == principal.UserID) {
+		return model.PermissionOwner, nil
+	}
+	if !ok {
+		return "", nil
+	}
+	grants, err := s.acl.Grants(ctx, todo.ID)
+	if err != nil {
+		return "", err
+	}
+	return effectivePermission(todo, principal, ok, grants), nil
+}
+
+// effectivePermission applies the rules of permission to already loaded
+// grants.
+func effectivePermission(todo model.Todo, principal auth.Principal, ok bool, grants []model.Grant) model.Permission {
+	if todo.CreatedBy == uuid.Nil {
+		return model.PermissionOwner
+	}
+	if !ok {
+		return ""
+	}
+	if todo.CreatedBy == principal.UserID {
+		return model.PermissionOwner
+	}
+	var best model.Permission
+	if slices.Contains(todo.Assignees, principal.UserID) {
+		best = model.PermissionEditor
+	}
+	for _, grant := range grants {
+		if grant.AppliesTo(principal.UserID, principal.Groups) && grant.Permission.Rank() > best.Rank() {
+			best = grant.Permission
+		}
+	}
+	return best
+}
+
+// normalizeSubject validates a grant subject and returns its canonical ID.
+func normalizeSubject(subjectType model.SubjectType, subjectID string) (string, error) {
+	switch subjectType {
+	case model.SubjectUser:
+		id, err := uuid.Parse(subjectID)
+		if err != nil || id == uuid.Nil {
+			return "", invalidField("subject_id", ViolationInvalid, "must be a user id")
+		}
+		return id.String(), nil
+	case model.SubjectGroup:
+		subjectID = strings.TrimSpace(subjectID)
+		if subjectID == "" {
+			return "", invalidField("subject_id", ViolationRequired, "is required")
+		}
+		if len(subjectID) > maxGroupLength {
+			var v violations
+			v.addLimit("subject_id", ViolationTooLong, maxGroupLength)
+			return "", v.err()
+		}
+		return subjectID, nil
+	default:
+		return "", invalidField("subject_type", ViolationInvalid, "must be user or group")
+	}
+}
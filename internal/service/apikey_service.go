@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/authz"
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+// API keys look like tdk_<prefix>_<secret>: 12 and 64 hex characters.
+const (
+	apiKeyMarker       = "tdk_"
+	apiKeyPrefixBytes  = 6
+	apiKeySecretBytes  = 32
+	lastUsedResolution = time.Minute
+)
+
+type CreateAPIKeyInput struct {
+	TenantID string
+	Name     string
+	Scopes   []string
+	// TTL is optional; zero means the key never expires.
+	TTL time.Duration
+}
+
+type APIKeyService struct {
+	store repository.APIKeyStore
+	now   func() time.Time
+}
+
+func NewAPIKeyService(store repository.APIKeyStore) *APIKeyService {
+	return &APIKeyService{store: store, now: time.Now}
+}
+
+// Create mints a key and returns it together with the plaintext key, which
+// is not stored and cannot be recovered later.
+func (s *APIKeyService) Create(ctx context.Context, input CreateAPIKeyInput) (model.APIKey, string, error) {
+	if err := tenant.Validate(input.TenantID); err != nil {
+		return model.APIKey{}, "", err
+	}
+	var v violations
+	checkName(&v, input.Name)
+	if len(input.Scopes) == 0 {
+		v.add("scopes", ViolationRequired, "must not be empty")
+	}
+	for i, scope := range input.Scopes {
+		if !authz.ValidScope(scope) {
+			v.add("scopes["+strconv.Itoa(i)+"]", ViolationInvalid, "is not a known scope")
+		}
+	}
+	if input.TTL < 0 {
+		v.add("ttl", ViolationOutOfRange, "must not be negative")
+	}
+	if err := v.err(); err != nil {
+		return model.APIKey{}, "", err
+	}
+
+	prefix, err := randomHex(apiKeyPrefixBytes)
+	if err != nil {
+		return model.APIKey{}, "", err
+	}
+	secret, err := randomHex(apiKeySecretBytes)
+	if err != nil {
+		return model.APIKey{}, "", err
+	}
+	raw := apiKeyMarker + prefix + "_" + secret
+	now := s.now()
+	key := model.APIKey{
+		ID:        uuid.New(),
+		TenantID:  input.TenantID,
+		Name:      input.Name,
+		Prefix:    prefix,
+		Hash:      hashAPIKey(raw),
+		Scopes:    input.Scopes,
+		CreatedAt: now,
+	}
+	if input.TTL > 0 {
+		key.ExpiresAt = now.Add(input.TTL)
+	}
+	if err := s.store.Create(ctx, key); err != nil {
+		return model.APIKey{}, "", err
+	}
+	return key, raw, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context, tenantID string) ([]model.APIKey, error) {
+	return s.store.List(ctx, tenantID)
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, id uuid.UUID) (bool, error) {
+	return s.store.Revoke(ctx, id, s.now())
+}
+
+// Rotate mints a replacement with the same tenant, name, scopes and lifetime,
+// and shortens the old key's expiry to grace from now so both keys work
+// while callers switch over.
+func (s *APIKeyService) Rotate(ctx context.Context, id uuid.UUID, grace time.Duration) (model.APIKey, string, error) {
+	if grace < 0 {
+		return model.APIKey{}, "", invalidField("grace_period", ViolationOutOfRange, "must not be negative")
+	}
+	old, err := s.store.Get(ctx, id)
+	if err != nil {
+		return model.APIKey{}, "", err
+	}
+	now := s.now()
+	if !old.Active(now) {
+		return model.APIKey{}, "", wrapValidation("cannot rotate a revoked or expired key")
+	}
+	var ttl time.Duration
+	if !old.ExpiresAt.IsZero() {
+		ttl = old.ExpiresAt.Sub(old.CreatedAt)
+	}
+	key, raw, err := s.Create(ctx, CreateAPIKeyInput{TenantID: old.TenantID, Name: old.Name, Scopes: old.Scopes, TTL: ttl})
+	if err != nil {
+		return model.APIKey{}, "", err
+	}
+	if expiry := now.Add(grace); old.ExpiresAt.IsZero() || expiry.Before(old.ExpiresAt) {
+		if err := s.store.SetExpiry(ctx, old.ID, expiry); err != nil {
+			return model.APIKey{}, "", err
+		}
+	}
+	return key, raw, nil
+}
+
+// AuthenticateAPIKey implements auth.APIKeyAuthenticator. The key acts as its
+// own identity: the principal's user ID is the key ID.
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, raw string) (auth.Principal, string, error) {
+	prefix, ok := parseAPIKey(raw)
+	if !ok {
+		return auth.Principal{}, "", auth.ErrInvalidAPIKey
+	}
+	key, err := s.store.GetByPrefix(ctx, prefix)
+	if errors.Is(err, repository.ErrNotFound) {
+		return auth.Principal{}, "", auth.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return auth.Principal{}, "", err
+	}
+	if subtle.ConstantTimeCompare(key.Hash, hashAPIKey(raw)) != 1 {
+		return auth.Principal{}, "", auth.ErrInvalidAPIKey
+	}
+	now := s.now()
+	if !key.Active(now) {
+		return auth.Principal{}, "", fmt.Errorf("%w: revoked or expired", auth.ErrInvalidAPIKey)
+	}
+	// Recording every use would turn each request into a write.
+	if now.Sub(key.LastUsedAt) >= lastUsedResolution {
+		if err := s.store.TouchLastUsed(ctx, key.ID, now); err != nil {
+			log.Printf("api key %s: recording last use failed: %v", key.Prefix, err)
+		}
+	}
+	return auth.Principal{UserID: key.ID, Scopes: key.Scopes}, key.TenantID, nil
+}
+
+func parseAPIKey(raw string) (string, bool) {
+	rest, ok := strings.CutPrefix(raw, apiKeyMarker)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 2*apiKeyPrefixBytes || len(secret) != 2*apiKeySecretBytes {
+		return "", false
+	}
+	return prefix, true
+}
+
+func hashAPIKey(raw string) []byte {
+	sum := sha256.Sum256([]byte(raw))
+	return sum[:]
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
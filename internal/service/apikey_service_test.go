@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/repository/memory"
+)
+
+func TestAPIKeyRotationKeepsBothKeysValidDuringGrace(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	keys := NewAPIKeyService(memory.NewAPIKeyStore())
+	keys.now = func() time.Time { return now }
+
+	if _, _, err := keys.Create(ctx, CreateAPIKeyInput{TenantID: "acme", Name: "billing", Scopes: []string{"todo:fly"}}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected unknown scope to be rejected, got %v", err)
+	}
+	old, oldRaw, err := keys.Create(ctx, CreateAPIKeyInput{TenantID: "acme", Name: "billing", Scopes: []string{"todo:read"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	principal, tenantID, err := keys.AuthenticateAPIKey(ctx, oldRaw)
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if tenantID != "acme" || principal.UserID != old.ID || len(principal.Scopes) != 1 {
+		t.Fatalf("unexpected principal %+v for tenant %q", principal, tenantID)
+	}
+	tampered := []byte(oldRaw)
+	tampered[len(tampered)-1] ^= 1
+	if _, _, err := keys.AuthenticateAPIKey(ctx, string(tampered)); !errors.Is(err, auth.ErrInvalidAPIKey) {
+		t.Fatalf("expected tampered key to be rejected, got %v", err)
+	}
+
+	_, newRaw, err := keys.Rotate(ctx, old.ID, time.Hour)
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	for _, raw := range []string{oldRaw, newRaw} {
+		if _, _, err := keys.AuthenticateAPIKey(ctx, raw); err != nil {
+			t.Fatalf("expected both keys valid during grace: %v", err)
+		}
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, _, err := keys.AuthenticateAPIKey(ctx, oldRaw); !errors.Is(err, auth.ErrInvalidAPIKey) {
+		t.Fatalf("expected old key to expire after grace, got %v", err)
+	}
+	if _, _, err := keys.AuthenticateAPIKey(ctx, newRaw); err != nil {
+		t.Fatalf("new key: %v", err)
+	}
+}
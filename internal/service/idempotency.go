@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+const (
+	// idempotencyLease is how long a reservation holds its key before the
+	// response is stored. It only matters when a request dies without
+	// completing or releasing its reservation; other requests with the key
+	// then wait this long before running it again.
+	idempotencyLease = time.Minute
+	// idempotencyPollInterval is how often a request waiting for another
+	// with the same key checks for its response.
+	idempotencyPollInterval = 50 * time.Millisecond
+)
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey attaches a client supplied idempotency key to ctx.
+// Create, BulkCreate and BulkCreatePartial replay the stored response for a
+// repeated key.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+func idempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+// idempotent runs fn once per tenant, operation and idempotency key. The first call
+// reserves the key before running fn; concurrent calls with the same key
+// wait for it to finish and get its stored response. A different payload
+// gets ErrIdempotencyKeyReused. If fn fails the reservation is released, so
+// the request can be retried.
+func idempotent[T any](ctx context.Context, s *Service, operation string, request any, fn func() (T, error)) (T, error) {
+	var zero T
+	key := idempotencyKeyFromContext(ctx)
+	if key == "" || s.idempotency == nil {
+		return fn()
+	}
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return zero, err
+	}
+	key = tenantID + ":" + operation + ":" + key
+
+	fingerprint, err := fingerprintRequest(operation, request)
+	if err != nil {
+		return zero, err
+	}
+
+	for {
+		now := s.now()
+		record, reserved, err := s.idempotency.Reserve(ctx, repository.IdempotencyRecord{
+			Key:         key,
+			Fingerprint: fingerprint,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(idempotencyLease),
+		})
+		if err != nil {
+			return zero, err
+		}
+		if reserved {
+			break
+		}
+		if record.Fingerprint != fingerprint {
+			return zero, ErrIdempotencyKeyReused
+		}
+		if record.Response != nil {
+			var out T
+			if err := json.Unmarshal(record.Response, &out); err != nil {
+				return zero, err
+			}
+			return out, nil
+		}
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-time.After(idempotencyPollInterval):
+		}
+	}
+
+	// The outcome is recorded even if the caller goes away meanwhile.
+	storeCtx := context.WithoutCancel(ctx)
+	out, err := fn()
+	var response []byte
+	if err == nil {
+		response, err = json.Marshal(out)
+	}
+	if err != nil {
+		if releaseErr := s.idempotency.Release(storeCtx, key); releaseErr != nil {
+			log.Printf("idempotency key %s: releasing reservation failed: %v", key, releaseErr)
+		}
+		return zero, err
+	}
+	if err := s.idempotency.Complete(storeCtx, key, response, s.now().Add(s.idempotencyTTL)); err != nil {
+		// fn's changes are committed, so report them. Retries wait until the
+		// reservation's lease runs out and then run fn again.
+		log.Printf("idempotency key %s: storing response failed: %v", key, err)
+	}
+	return out, nil
+}
+
+func fingerprintRequest(operation string, request any) (string, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(operation+":"), payload...))
+	return hex.EncodeToString(sum[:]), nil
+}
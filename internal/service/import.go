@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/events"
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+// maxImportErrors caps the line errors kept in an ImportResult so imports
+// of invalid data still run in constant memory.
+const maxImportErrors = 1000
+
+// ImportItem is one line of an import. Err reports a line the transport
+// could not decode; it counts as failed without being validated.
+type ImportItem struct {
+	Input CreateTodoInput
+	Err   error
+}
+
+// ImportLineError is the failure of one import line. Lines count from 1.
+type ImportLineError struct {
+	Line int
+	Err  error
+}
+
+// ImportResult summarises an import. Errors holds the first failures in
+// line order; Failed counts all of them.
+type ImportResult struct {
+	Created int
+	Failed  int
+	Errors  []ImportLineError
+}
+
+func (r *ImportResult) fail(line int, err error) {
+	r.Failed++
+	if len(r.Errors) < maxImportErrors {
+		r.Errors = append(r.Errors, ImportLineError{Line: line, Err: err})
+	}
+}
+
+// Import creates todos from items read one at a time from next until it
+// returns io.EOF. Items are validated through the worker pool and committed
+// in batches of the configured size, so the input never has to fit in
+// memory. Invalid lines, and every line of a batch the repository rejects
+// as a conflict or constraint violation, are reported in the result without
+// stopping the import. Any other error stops it; batches committed before
+// remain in place and are counted in the returned result.
+func (s *Service) Import(ctx context.Context, next func() (ImportItem, error)) (ImportResult, error) {
+	var result ImportResult
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return result, err
+	}
+	pool := s.buildPool(tenantID, auth.UserID(ctx))
+	inputs := make([]CreateTodoInput, 0, s.importBatchSize)
+	lines := make([]int, 0, s.importBatchSize)
+
+	flush := func() error {
+		if len(inputs) == 0 {
+			return nil
+		}
+		todos, errs, err := pool.RunAll(ctx, inputs)
+		if err != nil {
+			return err
+		}
+		valid := make([]model.Todo, 0, len(todos))
+		validLines := make([]int, 0, len(todos))
+		for i, err := range errs {
+			if err != nil {
+				result.fail(lines[i], err)
+				continue
+			}
+			valid = append(valid, todos[i])
+			validLines = append(validLines, lines[i])
+		}
+		inputs, lines = inputs[:0], lines[:0]
+		if len(valid) == 0 {
+			return nil
+		}
+		if err := s.repo.CreateBatch(s.withQuota(ctx, tenantID), valid); err != nil {
+			if !isRejectedBatch(err) {
+				return err
+			}
+			for _, line := range validLines {
+				result.fail(line, err)
+			}
+			return nil
+		}
+		result.Created += len(valid)
+		s.publish(ctx, events.TypeCreated, valid...)
+		return nil
+	}
+
+	line := 0
+	for {
+		item, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("import stopped after line %d: %w", line, err)
+		}
+		line++
+		if item.Err != nil {
+			result.fail(line, item.Err)
+			continue
+		}
+		inputs = append(inputs, item.Input)
+		lines = append(lines, line)
+		if len(inputs) >= s.importBatchSize {
+			if err := flush(); err != nil {
+				return result, fmt.Errorf("import stopped at line %d: %w", line, err)
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return result, fmt.Errorf("import stopped at line %d: %w", line, err)
+	}
+	return result, nil
+}
+
+// isRejectedBatch reports repository errors caused by the batch's content
+// rather than by the database being unable to take it.
+func isRejectedBatch(err error) bool {
+	return errors.Is(err, repository.ErrConflict) ||
+		errors.Is(err, repository.ErrForeignKeyViolation) ||
+		errors.Is(err, repository.ErrCheckViolation)
+}
@@ -2,143 +2,536 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/events"
 	"github.com/fuzail-ahmed/codex-test/internal/model"
 	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
 	"github.com/fuzail-ahmed/codex-test/internal/worker"
 )
 
 var (
-	ErrValidation = errors.New("validation error")
+	ErrValidation           = errors.New("validation error")
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused with a different request")
+	// ErrQuotaExceeded is the repository error, so inserts that go over the
+	// quota fail with it however they reach the repository.
+	ErrQuotaExceeded    = repository.ErrQuotaExceeded
+	ErrPermissionDenied = errors.New("permission denied")
 )
 
 type CreateTodoInput struct {
+	// ID is optional; a new UUID is generated when it is uuid.Nil.
+	ID          uuid.UUID
 	Title       string
 	Description string
+	Assignees   []uuid.UUID
+}
+
+type UpsertTodoInput struct {
+	Title       string
+	Description string
+	// Status defaults to pending when empty.
+	Status    model.Status
+	Assignees []uuid.UUID
 }
 
 type UpdateTodoInput struct {
 	Title       *string
 	Description *string
 	Status      *model.Status
+	Assignees   *[]uuid.UUID
+}
+
+// BulkItemResult is the outcome of a single item in a partial-success bulk
+// create. Exactly one of Todo or Err is meaningful; Err is always a
+// *ValidationError.
+type BulkItemResult struct {
+	Index int
+	Todo  model.Todo
+	Err   error
+}
+
+// bulkItemRecord is how a BulkItemResult is stored for idempotent replay.
+// An item fails either validation or with an ID that already exists.
+type bulkItemRecord struct {
+	Index      int
+	Todo       model.Todo
+	Err        *ValidationError
+	ConflictID uuid.UUID `json:",omitzero"`
+}
+
+func (r BulkItemResult) MarshalJSON() ([]byte, error) {
+	record := bulkItemRecord{Index: r.Index, Todo: r.Todo}
+	var conflict *idConflictError
+	switch {
+	case r.Err == nil, errors.As(r.Err, &record.Err):
+	case errors.As(r.Err, &conflict):
+		record.ConflictID = conflict.id
+	default:
+		return nil, fmt.Errorf("bulk item %d: cannot store %T", r.Index, r.Err)
+	}
+	return json.Marshal(record)
+}
+
+func (r *BulkItemResult) UnmarshalJSON(data []byte) error {
+	var record bulkItemRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return err
+	}
+	*r = BulkItemResult{Index: record.Index, Todo: record.Todo}
+	switch {
+	case record.Err != nil:
+		r.Err = record.Err
+	case record.ConflictID != uuid.Nil:
+		r.Err = &idConflictError{id: record.ConflictID}
+	}
+	return nil
 }
 
 type Service struct {
-	repo        repository.TodoRepository
-	workers     int
-	now         func() time.Time
-	idGenerator func() uuid.UUID
+	repo            repository.TodoRepository
+	workers         int
+	now             func() time.Time
+	idGenerator     func() uuid.UUID
+	idempotency     repository.IdempotencyStore
+	idempotencyTTL  time.Duration
+	defaultQuota    int
+	tenantQuotas    map[string]int
+	users           repository.UserRepository
+	acl             repository.ACLRepository
+	events          *events.Broker
+	importBatchSize int
+}
+
+type Option func(*Service)
+
+// WithIdempotency enables idempotency keys for Create, BulkCreate and
+// BulkCreatePartial, keeping
+// stored responses for ttl.
+func WithIdempotency(store repository.IdempotencyStore, ttl time.Duration) Option {
+	return func(s *Service) {
+		s.idempotency = store
+		s.idempotencyTTL = ttl
+	}
 }
 
-func New(repo repository.TodoRepository, workers int) *Service {
-	return &Service{
-		repo:        repo,
-		workers:     workers,
-		now:         time.Now,
-		idGenerator: uuid.New,
+// WithTenantQuotas caps the number of todos per tenant. overrides take
+// precedence over defaultQuota; a quota of zero means unlimited.
+func WithTenantQuotas(defaultQuota int, overrides map[string]int) Option {
+	return func(s *Service) {
+		s.defaultQuota = defaultQuota
+		s.tenantQuotas = overrides
 	}
 }
 
+// WithUsers makes the service reject assignees that are not known users.
+func WithUsers(users repository.UserRepository) Option {
+	return func(s *Service) {
+		s.users = users
+	}
+}
+
+// WithACL enforces per-todo permissions from owners, assignees and grants.
+// Without it every caller in a tenant can read and modify every todo.
+func WithACL(acl repository.ACLRepository) Option {
+	return func(s *Service) {
+		s.acl = acl
+	}
+}
+
+// WithEvents publishes every created, updated and deleted todo to broker and
+// enables Watch.
+func WithEvents(broker *events.Broker) Option {
+	return func(s *Service) {
+		s.events = broker
+	}
+}
+
+// WithImportBatchSize sets how many items Import validates and commits at a
+// time.
+func WithImportBatchSize(n int) Option {
+	return func(s *Service) {
+		s.importBatchSize = n
+	}
+}
+
+func New(repo repository.TodoRepository, workers int, opts ...Option) *Service {
+	s := &Service{
+		repo:            repo,
+		workers:         workers,
+		now:             time.Now,
+		idGenerator:     uuid.New,
+		importBatchSize: 1000,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
 func (s *Service) Create(ctx context.Context, input CreateTodoInput) (model.Todo, error) {
-	if err := validateCreate(input); err != nil {
+	if err := s.validateCreate(ctx, input); err != nil {
 		return model.Todo{}, err
 	}
-	now := s.now()
-	todo := model.Todo{
-		ID:          s.idGenerator(),
-		Title:       input.Title,
-		Description: input.Description,
-		Status:      model.StatusPending,
-		CreatedAt:   now,
-		UpdatedAt:   now,
-	}
-	if err := s.repo.Create(ctx, todo); err != nil {
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
 		return model.Todo{}, err
 	}
-	return todo, nil
+	ctx = s.withQuota(ctx, tenantID)
+	return idempotent(ctx, s, "create", input, func() (model.Todo, error) {
+		todo := s.newTodo(tenantID, auth.UserID(ctx), input)
+		if err := s.repo.Create(ctx, todo); err != nil {
+			return model.Todo{}, err
+		}
+		s.publish(ctx, events.TypeCreated, todo)
+		return todo, nil
+	})
 }
 
 func (s *Service) BulkCreate(ctx context.Context, inputs []CreateTodoInput) ([]model.Todo, error) {
 	if len(inputs) == 0 {
-		return nil, wrapValidation("items must not be empty")
+		return nil, invalidField("items", ViolationRequired, "must not be empty")
+	}
+
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = s.withQuota(ctx, tenantID)
+	return idempotent(ctx, s, "bulk_create", inputs, func() ([]model.Todo, error) {
+		todos, errs, err := s.buildPool(tenantID, auth.UserID(ctx)).RunAll(ctx, inputs)
+		if err != nil {
+			return nil, err
+		}
+		// Report the violations of every item together; any other item
+		// error fails the batch as is.
+		var all violations
+		for i, err := range errs {
+			var validation *ValidationError
+			switch {
+			case err == nil:
+			case errors.As(err, &validation):
+				all = append(all, validation.prefixed(itemPath(i)).Violations...)
+			default:
+				return nil, err
+			}
+		}
+		if err := all.err(); err != nil {
+			return nil, err
+		}
+
+		if err := s.repo.CreateBatch(ctx, todos); err != nil {
+			return nil, err
+		}
+		s.publish(ctx, events.TypeCreated, todos...)
+		return todos, nil
+	})
+}
+
+// BulkCreatePartial validates every item, inserts the valid ones in a single
+// batch and reports a result per input index. Item validation failures and
+// IDs that already exist do not fail the call; any other error, such as a
+// failed assignee lookup or a repository error, does. Like BulkCreate it
+// honours idempotency keys, separately from them.
+func (s *Service) BulkCreatePartial(ctx context.Context, inputs []CreateTodoInput) ([]BulkItemResult, error) {
+	if len(inputs) == 0 {
+		return nil, invalidField("items", ViolationRequired, "must not be empty")
+	}
+
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = s.withQuota(ctx, tenantID)
+	return idempotent(ctx, s, "bulk_create_partial", inputs, func() ([]BulkItemResult, error) {
+		todos, errs, err := s.buildPool(tenantID, auth.UserID(ctx)).RunAll(ctx, inputs)
+		if err != nil {
+			return nil, err
+		}
+
+		results := make([]BulkItemResult, len(inputs))
+		valid := make([]model.Todo, 0, len(inputs))
+		validIndexes := make([]int, 0, len(inputs))
+		for i := range inputs {
+			var validation *ValidationError
+			switch {
+			case errs[i] == nil:
+				results[i] = BulkItemResult{Index: i, Todo: todos[i]}
+				valid = append(valid, todos[i])
+				validIndexes = append(validIndexes, i)
+			case errors.As(errs[i], &validation):
+				results[i] = BulkItemResult{Index: i, Err: validation.prefixed(itemPath(i))}
+			default:
+				return nil, errs[i]
+			}
+		}
+
+		if len(valid) == 0 {
+			return results, nil
+		}
+		err = s.repo.CreateBatch(ctx, valid)
+		if errors.Is(err, repository.ErrConflict) {
+			// Find the conflicting items by inserting one at a time.
+			valid, err = s.createEach(ctx, valid, validIndexes, results)
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.publish(ctx, events.TypeCreated, valid...)
+		return results, nil
+	})
+}
+
+// createEach inserts todos one by one and records a conflict in results for
+// every ID that already exists. indexes maps each todo to its result. It
+// returns the todos that were created.
+func (s *Service) createEach(ctx context.Context, todos []model.Todo, indexes []int, results []BulkItemResult) ([]model.Todo, error) {
+	created := make([]model.Todo, 0, len(todos))
+	for j, todo := range todos {
+		err := s.repo.Create(ctx, todo)
+		switch {
+		case err == nil:
+			created = append(created, todo)
+		case errors.Is(err, repository.ErrConflict):
+			results[indexes[j]] = BulkItemResult{Index: indexes[j], Err: &idConflictError{id: todo.ID}}
+		default:
+			return nil, err
+		}
 	}
+	return created, nil
+}
+
+// idConflictError reports a bulk item whose ID is already taken.
+type idConflictError struct {
+	id uuid.UUID
+}
+
+func (e *idConflictError) Error() string {
+	return fmt.Sprintf("todo %s already exists", e.id)
+}
+
+func (e *idConflictError) Unwrap() error {
+	return repository.ErrConflict
+}
 
-	pool := worker.Pool[CreateTodoInput, model.Todo]{
+func (s *Service) buildPool(tenantID string, createdBy uuid.UUID) worker.Pool[CreateTodoInput, model.Todo] {
+	return worker.Pool[CreateTodoInput, model.Todo]{
 		Workers: s.workers,
 		Work: func(ctx context.Context, input CreateTodoInput) (model.Todo, error) {
-			if err := validateCreate(input); err != nil {
+			if err := s.validateCreate(ctx, input); err != nil {
 				return model.Todo{}, err
 			}
-			now := s.now()
-			return model.Todo{
-				ID:          s.idGenerator(),
-				Title:       input.Title,
-				Description: input.Description,
-				Status:      model.StatusPending,
-				CreatedAt:   now,
-				UpdatedAt:   now,
-			}, nil
+			return s.newTodo(tenantID, createdBy, input), nil
 		},
 	}
+}
 
-	todos, err := pool.Run(ctx, inputs)
-	if err != nil {
-		return nil, err
+func (s *Service) newTodo(tenantID string, createdBy uuid.UUID, input CreateTodoInput) model.Todo {
+	id := input.ID
+	if id == uuid.Nil {
+		id = s.idGenerator()
 	}
-
-	if err := s.repo.CreateBatch(ctx, todos); err != nil {
-		return nil, err
+	now := s.now()
+	return model.Todo{
+		ID:          id,
+		TenantID:    tenantID,
+		Title:       input.Title,
+		Description: input.Description,
+		Status:      model.StatusPending,
+		CreatedBy:   createdBy,
+		Assignees:   input.Assignees,
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
-	return todos, nil
 }
 
 func (s *Service) Get(ctx context.Context, id uuid.UUID) (model.Todo, error) {
-	return s.repo.Get(ctx, id)
+	return s.getAuthorized(ctx, id, model.PermissionViewer)
 }
 
 func (s *Service) List(ctx context.Context, filter repository.ListFilter) ([]model.Todo, error) {
+	if s.acl != nil {
+		principal, _ := auth.PrincipalFromContext(ctx)
+		filter.VisibleTo = &repository.Viewer{UserID: principal.UserID, Groups: principal.Groups}
+	}
 	return s.repo.List(ctx, filter)
 }
 
+// Export calls fn for every todo List would return across all pages,
+// newest first. Limit and Offset are ignored.
+func (s *Service) Export(ctx context.Context, filter repository.ListFilter, fn func(model.Todo) error) error {
+	if s.acl != nil {
+		principal, _ := auth.PrincipalFromContext(ctx)
+		filter.VisibleTo = &repository.Viewer{UserID: principal.UserID, Groups: principal.Groups}
+	}
+	return s.repo.Export(ctx, filter, fn)
+}
+
 func (s *Service) Update(ctx context.Context, id uuid.UUID, input UpdateTodoInput) (model.Todo, error) {
-	existing, err := s.repo.Get(ctx, id)
+	existing, err := s.getAuthorized(ctx, id, model.PermissionEditor)
 	if err != nil {
 		return model.Todo{}, err
 	}
 
+	if input.Title == nil && input.Description == nil && input.Status == nil && input.Assignees == nil {
+		return model.Todo{}, wrapValidation("no fields to update")
+	}
+	var v violations
 	if input.Title != nil {
-		if err := validateTitle(*input.Title); err != nil {
-			return model.Todo{}, err
-		}
+		checkTitle(&v, *input.Title)
 		existing.Title = *input.Title
 	}
 	if input.Description != nil {
-		if err := validateDescription(*input.Description); err != nil {
-			return model.Todo{}, err
-		}
+		checkDescription(&v, *input.Description)
 		existing.Description = *input.Description
 	}
 	if input.Status != nil {
-		if err := validateStatus(*input.Status); err != nil {
+		checkStatus(&v, *input.Status)
+		existing.Status = *input.Status
+	}
+	if input.Assignees != nil {
+		checkAssigneeList(&v, *input.Assignees)
+		if err := s.checkAssignees(ctx, &v, *input.Assignees); err != nil {
 			return model.Todo{}, err
 		}
-		existing.Status = *input.Status
+		existing.Assignees = *input.Assignees
 	}
-	if input.Title == nil && input.Description == nil && input.Status == nil {
-		return model.Todo{}, wrapValidation("no fields to update")
+	if err := v.err(); err != nil {
+		return model.Todo{}, err
 	}
 	existing.UpdatedAt = s.now()
 
 	if err := s.repo.Update(ctx, existing); err != nil {
 		return model.Todo{}, err
 	}
+	s.publish(ctx, events.TypeUpdated, existing)
 	return existing, nil
 }
 
+// Upsert creates the todo with the given ID or replaces all of its fields.
+// The returned flag reports whether the todo was created.
+func (s *Service) Upsert(ctx context.Context, id uuid.UUID, input UpsertTodoInput) (model.Todo, bool, error) {
+	if id == uuid.Nil {
+		return model.Todo{}, false, invalidField("id", ViolationRequired, "is required")
+	}
+	if input.Status == "" {
+		input.Status = model.StatusPending
+	}
+	v := validateUpsert(input)
+	if err := s.checkAssignees(ctx, &v, input.Assignees); err != nil {
+		return model.Todo{}, false, err
+	}
+	if err := v.err(); err != nil {
+		return model.Todo{}, false, err
+	}
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return model.Todo{}, false, err
+	}
+	if s.acl != nil {
+		existing, err := s.repo.Get(ctx, id)
+		switch {
+		case err == nil:
+			if err := s.authorize(ctx, existing, model.PermissionEditor); err != nil {
+				return model.Todo{}, false, err
+			}
+		case !errors.Is(err, repository.ErrNotFound):
+			return model.Todo{}, false, err
+		}
+	}
+	now := s.now()
+	todo, created, err := s.repo.Upsert(s.withQuota(ctx, tenantID), model.Todo{
+		ID:          id,
+		TenantID:    tenantID,
+		Title:       input.Title,
+		Description: input.Description,
+		Status:      input.Status,
+		CreatedBy:   auth.UserID(ctx),
+		Assignees:   input.Assignees,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return model.Todo{}, false, err
+	}
+	if created {
+		s.publish(ctx, events.TypeCreated, todo)
+	} else {
+		s.publish(ctx, events.TypeUpdated, todo)
+	}
+	return todo, created, nil
+}
+
 func (s *Service) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
-	return s.repo.Delete(ctx, id)
+	if s.acl == nil && s.events == nil {
+		return s.repo.Delete(ctx, id)
+	}
+	existing, err := s.getAuthorized(ctx, id, model.PermissionOwner)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	// Build the event first: deleting the todo also deletes its grants.
+	deleted := s.event(ctx, events.TypeDeleted, existing)
+	ok, err := s.repo.Delete(ctx, id)
+	if err != nil || !ok {
+		return ok, err
+	}
+	if s.events != nil {
+		s.events.Publish(deleted)
+	}
+	return true, nil
+}
+
+func (s *Service) quotaFor(tenantID string) int {
+	if quota, ok := s.tenantQuotas[tenantID]; ok {
+		return quota
+	}
+	return s.defaultQuota
+}
+
+// withQuota hands the tenant's quota to the repository, which enforces it
+// atomically with each insert.
+func (s *Service) withQuota(ctx context.Context, tenantID string) context.Context {
+	return repository.WithQuota(ctx, s.quotaFor(tenantID))
+}
+
+func (s *Service) validateCreate(ctx context.Context, input CreateTodoInput) error {
+	v := validateCreate(input)
+	if err := s.checkAssignees(ctx, &v, input.Assignees); err != nil {
+		return err
+	}
+	return v.err()
+}
+
+// checkAssignees records a violation for every assignee that is not a known
+// user in the tenant. It is a no-op without a user repository; the returned
+// error is reserved for lookup failures.
+func (s *Service) checkAssignees(ctx context.Context, v *violations, assignees []uuid.UUID) error {
+	if s.users == nil || len(assignees) > maxAssignees {
+		return nil
+	}
+	for i, id := range assignees {
+		if id == uuid.Nil {
+			continue
+		}
+		if _, err := s.users.Get(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				v.add("assignees["+strconv.Itoa(i)+"]", ViolationUnknown, "is not a known user")
+				continue
+			}
+			return err
+		}
+	}
+	return nil
 }
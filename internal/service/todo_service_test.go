@@ -2,17 +2,34 @@ package service
 
 import (
 	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/events"
+	"github.com/fuzail-ahmed/codex-test/internal/model"
 	"github.com/fuzail-ahmed/codex-test/internal/repository"
 	"github.com/fuzail-ahmed/codex-test/internal/repository/memory"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
 )
 
+func tenantCtx() context.Context {
+	return tenant.WithID(context.Background(), "acme")
+}
+
 func TestBulkCreate_AllOrNothing(t *testing.T) {
 	repo := memory.New()
 	svc := New(repo, 4)
 
-	_, err := svc.BulkCreate(context.Background(), []CreateTodoInput{
+	_, err := svc.BulkCreate(tenantCtx(), []CreateTodoInput{
 		{Title: "valid", Description: "ok"},
 		{Title: "", Description: "invalid"},
 	})
@@ -20,7 +37,7 @@ func TestBulkCreate_AllOrNothing(t *testing.T) {
 		t.Fatalf("expected validation error")
 	}
 
-	items, err := repo.List(context.Background(), repository.ListFilter{Limit: 10, Offset: 0})
+	items, err := repo.List(tenantCtx(), repository.ListFilter{Limit: 10, Offset: 0})
 	if err != nil {
 		t.Fatalf("list error: %v", err)
 	}
@@ -28,3 +45,505 @@ func TestBulkCreate_AllOrNothing(t *testing.T) {
 		t.Fatalf("expected no items, got %d", len(items))
 	}
 }
+
+func TestBulkCreatePartial_ReportsEveryInvalidItem(t *testing.T) {
+	repo := memory.New()
+	svc := New(repo, 4)
+
+	results, err := svc.BulkCreatePartial(tenantCtx(), []CreateTodoInput{
+		{Title: "first", Description: "ok"},
+		{Title: "", Description: "missing title"},
+		{Title: "third", Description: "ok"},
+		{Title: "   ", Description: "blank title"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	for i, wantErr := range []bool{false, true, false, true} {
+		if results[i].Index != i {
+			t.Fatalf("result %d has index %d", i, results[i].Index)
+		}
+		if gotErr := results[i].Err != nil; gotErr != wantErr {
+			t.Fatalf("result %d: expected error=%v, got %v", i, wantErr, results[i].Err)
+		}
+	}
+
+	items, err := repo.List(tenantCtx(), repository.ListFilter{Limit: 10, Offset: 0})
+	if err != nil {
+		t.Fatalf("list error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+}
+
+func TestBulkCreatePartial_IdempotencyKeyReplaysResults(t *testing.T) {
+	repo := memory.New()
+	svc := New(repo, 4, WithIdempotency(memory.NewIdempotencyStore(), time.Hour))
+	ctx := WithIdempotencyKey(tenantCtx(), "key-1")
+	inputs := []CreateTodoInput{{Title: "first"}, {Title: ""}}
+
+	first, err := svc.BulkCreatePartial(ctx, inputs)
+	if err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	second, err := svc.BulkCreatePartial(ctx, inputs)
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if second[0].Todo.ID != first[0].Todo.ID {
+		t.Fatalf("expected replayed id %s, got %s", first[0].Todo.ID, second[0].Todo.ID)
+	}
+	var validation *ValidationError
+	if !errors.As(second[1].Err, &validation) || validation.Violations[0].Field != "items[1].title" {
+		t.Fatalf("expected replayed validation error for items[1].title, got %v", second[1].Err)
+	}
+	if count, _ := repo.Count(tenantCtx()); count != 1 {
+		t.Fatalf("expected 1 todo, got %d", count)
+	}
+
+	// The atomic mode keeps its own keys.
+	if _, err := svc.BulkCreate(ctx, []CreateTodoInput{{Title: "atomic"}}); err != nil {
+		t.Fatalf("atomic bulk create with the same key: %v", err)
+	}
+}
+
+func TestBulkCreatePartial_ReportsExistingID(t *testing.T) {
+	repo := memory.New()
+	svc := New(repo, 4, WithIdempotency(memory.NewIdempotencyStore(), time.Hour))
+	existing, err := svc.Create(tenantCtx(), CreateTodoInput{Title: "existing"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	ctx := WithIdempotencyKey(tenantCtx(), "key-1")
+	inputs := []CreateTodoInput{{Title: "first"}, {ID: existing.ID, Title: "duplicate"}, {Title: "third"}}
+
+	results, err := svc.BulkCreatePartial(ctx, inputs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Fatalf("expected the other items to be created, got %v and %v", results[0].Err, results[2].Err)
+	}
+	if !errors.Is(results[1].Err, repository.ErrConflict) {
+		t.Fatalf("expected a conflict for the existing id, got %v", results[1].Err)
+	}
+	if count, _ := repo.Count(tenantCtx()); count != 3 {
+		t.Fatalf("expected 3 todos, got %d", count)
+	}
+
+	replayed, err := svc.BulkCreatePartial(ctx, inputs)
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if !errors.Is(replayed[1].Err, repository.ErrConflict) || replayed[1].Err.Error() != results[1].Err.Error() {
+		t.Fatalf("expected the conflict to be replayed, got %v", replayed[1].Err)
+	}
+}
+
+func TestCreate_IdempotencyKeyReplaysResponse(t *testing.T) {
+	repo := memory.New()
+	svc := New(repo, 4, WithIdempotency(memory.NewIdempotencyStore(), time.Hour))
+	ctx := WithIdempotencyKey(tenantCtx(), "key-1")
+
+	first, err := svc.Create(ctx, CreateTodoInput{Title: "write docs"})
+	if err != nil {
+		t.Fatalf("create error: %v", err)
+	}
+	second, err := svc.Create(ctx, CreateTodoInput{Title: "write docs"})
+	if err != nil {
+		t.Fatalf("retry error: %v", err)
+	}
+	if first.ID != second.ID {
+		t.Fatalf("expected replayed id %s, got %s", first.ID, second.ID)
+	}
+
+	_, err = svc.Create(ctx, CreateTodoInput{Title: "something else"})
+	if !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("expected ErrIdempotencyKeyReused, got %v", err)
+	}
+
+	items, err := repo.List(tenantCtx(), repository.ListFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("list error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+}
+
+func TestUpsert_CreatesThenReplacesWithStableID(t *testing.T) {
+	repo := memory.New()
+	svc := New(repo, 4)
+	id := uuid.New()
+
+	created, isNew, err := svc.Upsert(tenantCtx(), id, UpsertTodoInput{Title: "mirror", Description: "v1"})
+	if err != nil {
+		t.Fatalf("upsert error: %v", err)
+	}
+	if !isNew || created.ID != id {
+		t.Fatalf("expected new todo with id %s, got %s (created=%v)", id, created.ID, isNew)
+	}
+
+	replaced, isNew, err := svc.Upsert(tenantCtx(), id, UpsertTodoInput{Title: "mirror", Description: "v2", Status: "done"})
+	if err != nil {
+		t.Fatalf("upsert error: %v", err)
+	}
+	if isNew {
+		t.Fatalf("expected replace, got create")
+	}
+	if replaced.Description != "v2" || replaced.Status != "done" || !replaced.CreatedAt.Equal(created.CreatedAt) {
+		t.Fatalf("unexpected replaced todo: %+v", replaced)
+	}
+}
+
+func TestCreate_ConcurrentIdempotentRequestsRunOnce(t *testing.T) {
+	repo := memory.New()
+	svc := New(repo, 4, WithIdempotency(memory.NewIdempotencyStore(), time.Hour))
+	ctx := WithIdempotencyKey(tenantCtx(), "key-1")
+
+	ids := make([]uuid.UUID, 10)
+	var wg sync.WaitGroup
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			todo, err := svc.Create(ctx, CreateTodoInput{Title: "write docs"})
+			if err != nil {
+				t.Errorf("create error: %v", err)
+			}
+			ids[i] = todo.ID
+		}()
+	}
+	wg.Wait()
+	for _, id := range ids[1:] {
+		if id != ids[0] {
+			t.Fatalf("expected every request to get %s, got %s", ids[0], id)
+		}
+	}
+	if count, _ := repo.Count(tenantCtx()); count != 1 {
+		t.Fatalf("expected 1 todo, got %d", count)
+	}
+}
+
+func TestTenantIsolationAndQuota(t *testing.T) {
+	repo := memory.New()
+	svc := New(repo, 4, WithTenantQuotas(0, map[string]int{"acme": 1}))
+	acme := tenantCtx()
+	globex := tenant.WithID(context.Background(), "globex")
+
+	todo, err := svc.Create(acme, CreateTodoInput{Title: "acme only"})
+	if err != nil {
+		t.Fatalf("create error: %v", err)
+	}
+	if todo.TenantID != "acme" {
+		t.Fatalf("expected tenant acme, got %q", todo.TenantID)
+	}
+	if _, err := svc.Get(globex, todo.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected cross-tenant read to be not found, got %v", err)
+	}
+	if _, err := svc.Create(acme, CreateTodoInput{Title: "over quota"}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if _, err := svc.Create(globex, CreateTodoInput{Title: "unlimited"}); err != nil {
+		t.Fatalf("expected globex create to succeed, got %v", err)
+	}
+	if _, err := svc.Create(context.Background(), CreateTodoInput{Title: "no tenant"}); !errors.Is(err, tenant.ErrMissing) {
+		t.Fatalf("expected tenant.ErrMissing, got %v", err)
+	}
+}
+
+func TestQuotaUnderConcurrentCreates(t *testing.T) {
+	repo := memory.New()
+	svc := New(repo, 4, WithTenantQuotas(5, nil))
+
+	var wg sync.WaitGroup
+	var created atomic.Int64
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.Create(tenantCtx(), CreateTodoInput{Title: "racing"})
+			switch {
+			case err == nil:
+				created.Add(1)
+			case !errors.Is(err, ErrQuotaExceeded):
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if created.Load() != 5 {
+		t.Fatalf("expected 5 creates within quota, got %d", created.Load())
+	}
+	if _, err := svc.BulkCreatePartial(tenantCtx(), []CreateTodoInput{{Title: "one more"}}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded from partial bulk create, got %v", err)
+	}
+}
+
+func TestOwnershipAndAssignees(t *testing.T) {
+	repo := memory.New()
+	userRepo := memory.NewUserRepo()
+	users := NewUserService(userRepo)
+	svc := New(repo, 2, WithUsers(userRepo))
+
+	alice, err := users.Create(tenantCtx(), CreateUserInput{Email: "Alice@example.com", Name: "Alice"})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	bob, err := users.Create(tenantCtx(), CreateUserInput{Email: "bob@example.com", Name: "Bob"})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if _, err := users.Create(tenantCtx(), CreateUserInput{Email: "alice@example.com", Name: "Again"}); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("expected duplicate email conflict, got %v", err)
+	}
+
+	aliceCtx := auth.WithPrincipal(tenantCtx(), auth.Principal{UserID: alice.ID})
+	todo, err := svc.Create(aliceCtx, CreateTodoInput{Title: "review", Assignees: []uuid.UUID{bob.ID}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if todo.CreatedBy != alice.ID {
+		t.Fatalf("expected created_by %s, got %s", alice.ID, todo.CreatedBy)
+	}
+	if _, err := svc.Create(aliceCtx, CreateTodoInput{Title: "bad", Assignees: []uuid.UUID{uuid.New()}}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected unknown assignee to fail validation, got %v", err)
+	}
+
+	assigned, err := svc.List(tenantCtx(), repository.ListFilter{AssignedTo: bob.ID})
+	if err != nil || len(assigned) != 1 {
+		t.Fatalf("expected 1 todo assigned to bob, got %d (%v)", len(assigned), err)
+	}
+	created, err := svc.List(tenantCtx(), repository.ListFilter{CreatedBy: bob.ID})
+	if err != nil || len(created) != 0 {
+		t.Fatalf("expected no todos created by bob, got %d (%v)", len(created), err)
+	}
+
+	// A full replace by another user keeps the original owner.
+	bobCtx := auth.WithPrincipal(tenantCtx(), auth.Principal{UserID: bob.ID})
+	replaced, _, err := svc.Upsert(bobCtx, todo.ID, UpsertTodoInput{Title: "review v2"})
+	if err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if replaced.CreatedBy != alice.ID || len(replaced.Assignees) != 0 {
+		t.Fatalf("unexpected owner %s or assignees %v after replace", replaced.CreatedBy, replaced.Assignees)
+	}
+}
+
+func TestSharingEnforcesPermissions(t *testing.T) {
+	repo := memory.New()
+	svc := New(repo, 2, WithACL(repo))
+
+	owner := auth.WithPrincipal(tenantCtx(), auth.Principal{UserID: uuid.New()})
+	viewerID := uuid.New()
+	viewer := auth.WithPrincipal(tenantCtx(), auth.Principal{UserID: viewerID})
+	teammate := auth.WithPrincipal(tenantCtx(), auth.Principal{UserID: uuid.New(), Groups: []string{"eng"}})
+	stranger := auth.WithPrincipal(tenantCtx(), auth.Principal{UserID: uuid.New()})
+
+	todo, err := svc.Create(owner, CreateTodoInput{Title: "private"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := svc.Get(stranger, todo.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected stranger to get not found, got %v", err)
+	}
+	if items, _ := svc.List(stranger, repository.ListFilter{}); len(items) != 0 {
+		t.Fatalf("expected stranger to list nothing, got %d", len(items))
+	}
+
+	if _, err := svc.Share(owner, todo.ID, ShareTodoInput{SubjectType: model.SubjectUser, SubjectID: viewerID.String(), Permission: model.PermissionViewer}); err != nil {
+		t.Fatalf("share with user: %v", err)
+	}
+	if _, err := svc.Share(owner, todo.ID, ShareTodoInput{SubjectType: model.SubjectGroup, SubjectID: "eng", Permission: model.PermissionEditor}); err != nil {
+		t.Fatalf("share with group: %v", err)
+	}
+
+	if _, err := svc.Get(viewer, todo.ID); err != nil {
+		t.Fatalf("viewer get: %v", err)
+	}
+	title := "edited"
+	if _, err := svc.Update(viewer, todo.ID, UpdateTodoInput{Title: &title}); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("expected viewer update to be denied, got %v", err)
+	}
+	if _, err := svc.Update(teammate, todo.ID, UpdateTodoInput{Title: &title}); err != nil {
+		t.Fatalf("group editor update: %v", err)
+	}
+	if _, err := svc.Delete(teammate, todo.ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("expected editor delete to be denied, got %v", err)
+	}
+	if items, _ := svc.List(teammate, repository.ListFilter{}); len(items) != 1 {
+		t.Fatalf("expected teammate to list the shared todo, got %d", len(items))
+	}
+	if deleted, err := svc.Delete(owner, todo.ID); err != nil || !deleted {
+		t.Fatalf("owner delete: %v %v", deleted, err)
+	}
+}
+
+func TestBulkCreate_ReportsViolationsWithItemPaths(t *testing.T) {
+	svc := New(memory.New(), 4)
+
+	_, err := svc.BulkCreate(tenantCtx(), []CreateTodoInput{
+		{Title: "valid"},
+		{Title: ""},
+		{Title: "ok", Description: strings.Repeat("x", maxDescriptionLength+1)},
+	})
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(validation.Violations) != 2 {
+		t.Fatalf("expected 2 violations, got %+v", validation.Violations)
+	}
+	byField := make(map[string]FieldViolation)
+	for _, v := range validation.Violations {
+		byField[v.Field] = v
+	}
+	if v := byField["items[1].title"]; v.Code != ViolationRequired {
+		t.Fatalf("expected required title on item 1, got %+v", validation.Violations)
+	}
+	if v := byField["items[2].description"]; v.Code != ViolationTooLong || v.Limit != maxDescriptionLength {
+		t.Fatalf("expected too long description on item 2, got %+v", validation.Violations)
+	}
+}
+
+func TestWatchDeliversVisibleChanges(t *testing.T) {
+	repo := memory.New()
+	svc := New(repo, 2, WithACL(repo), WithEvents(events.NewBroker()))
+
+	owner := auth.WithPrincipal(tenantCtx(), auth.Principal{UserID: uuid.New()})
+	viewerID := uuid.New()
+	viewer := auth.WithPrincipal(tenantCtx(), auth.Principal{UserID: viewerID})
+
+	sub, err := svc.Watch(viewer, WatchFilter{}, "")
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	defer sub.Close()
+
+	if _, err := svc.Create(owner, CreateTodoInput{Title: "hidden"}); err != nil {
+		t.Fatalf("create hidden: %v", err)
+	}
+	todo, err := svc.Create(owner, CreateTodoInput{Title: "shared"})
+	if err != nil {
+		t.Fatalf("create shared: %v", err)
+	}
+	if _, err := svc.Share(owner, todo.ID, ShareTodoInput{SubjectType: model.SubjectUser, SubjectID: viewerID.String(), Permission: model.PermissionViewer}); err != nil {
+		t.Fatalf("share: %v", err)
+	}
+	if _, err := svc.Delete(owner, todo.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	for _, want := range []events.Type{events.TypeUpdated, events.TypeDeleted} {
+		select {
+		case e := <-sub.Events():
+			if e.Type != want || e.Todo.ID != todo.ID {
+				t.Fatalf("expected %s of the shared todo, got %s of %q", want, e.Type, e.Todo.Title)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+	if _, err := svc.Watch(viewer, WatchFilter{Types: []events.Type{"moved"}}, ""); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected unknown type to be rejected, got %v", err)
+	}
+}
+
+func TestImportCommitsInBatchesAndReportsLines(t *testing.T) {
+	repo := memory.New()
+	svc := New(repo, 2, WithImportBatchSize(2))
+
+	existing, err := svc.Create(tenantCtx(), CreateTodoInput{Title: "existing"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	items := []ImportItem{
+		{Input: CreateTodoInput{Title: "one"}},
+		{Input: CreateTodoInput{Title: ""}},
+		{Err: errors.New("malformed")},
+		{Input: CreateTodoInput{Title: "two"}},
+		{Input: CreateTodoInput{ID: existing.ID, Title: "dup"}},
+		{Input: CreateTodoInput{Title: "three"}},
+		{Input: CreateTodoInput{Title: "four"}},
+	}
+	next := func() (ImportItem, error) {
+		if len(items) == 0 {
+			return ImportItem{}, io.EOF
+		}
+		item := items[0]
+		items = items[1:]
+		return item, nil
+	}
+	result, err := svc.Import(tenantCtx(), next)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.Created != 3 || result.Failed != 4 {
+		t.Fatalf("expected 3 created and 4 failed, got %+v", result)
+	}
+	var lines []int
+	for _, lineErr := range result.Errors {
+		lines = append(lines, lineErr.Line)
+	}
+	// Line 5 shares a batch with line 4, so the conflict rejects both.
+	if want := []int{2, 3, 4, 5}; !slices.Equal(lines, want) {
+		t.Fatalf("expected failed lines %v, got %v", want, lines)
+	}
+	if !errors.Is(result.Errors[0].Err, ErrValidation) || !errors.Is(result.Errors[3].Err, repository.ErrConflict) {
+		t.Fatalf("unexpected line errors: %+v", result.Errors)
+	}
+	if todos, _ := svc.List(tenantCtx(), repository.ListFilter{}); len(todos) != 4 {
+		t.Fatalf("expected 4 stored todos, got %d", len(todos))
+	}
+}
+
+func TestExportVisitsEveryVisibleTodoNewestFirst(t *testing.T) {
+	repo := memory.New()
+	svc := New(repo, 2, WithACL(repo))
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tick := 0
+	svc.now = func() time.Time {
+		tick++
+		return base.Add(time.Duration(tick) * time.Minute)
+	}
+
+	owner := auth.WithPrincipal(tenantCtx(), auth.Principal{UserID: uuid.New()})
+	stranger := auth.WithPrincipal(tenantCtx(), auth.Principal{UserID: uuid.New()})
+	var want []string
+	for i := 0; i < 250; i++ {
+		todo, err := svc.Create(owner, CreateTodoInput{Title: "todo"})
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		want = append([]string{todo.ID.String()}, want...)
+	}
+	if _, err := svc.Create(stranger, CreateTodoInput{Title: "theirs"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	var got []string
+	err := svc.Export(owner, repository.ListFilter{Limit: 10}, func(todo model.Todo) error {
+		got = append(got, todo.ID.String())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected all %d owned todos newest first, got %d", len(want), len(got))
+	}
+
+	stop := errors.New("client gone")
+	visited := 0
+	err = svc.Export(owner, repository.ListFilter{}, func(model.Todo) error {
+		visited++
+		return stop
+	})
+	if !errors.Is(err, stop) || visited != 1 {
+		t.Fatalf("expected export to stop at the first callback error, got %v after %d", err, visited)
+	}
+}
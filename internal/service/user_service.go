@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+)
+
+type CreateUserInput struct {
+	Email string
+	Name  string
+}
+
+type UserService struct {
+	repo        repository.UserRepository
+	now         func() time.Time
+	idGenerator func() uuid.UUID
+}
+
+func NewUserService(repo repository.UserRepository) *UserService {
+	return &UserService{
+		repo:        repo,
+		now:         time.Now,
+		idGenerator: uuid.New,
+	}
+}
+
+func (s *UserService) Create(ctx context.Context, input CreateUserInput) (model.User, error) {
+	input.Email = strings.ToLower(strings.TrimSpace(input.Email))
+	var v violations
+	checkEmail(&v, input.Email)
+	checkName(&v, input.Name)
+	if err := v.err(); err != nil {
+		return model.User{}, err
+	}
+	user := model.User{
+		ID:        s.idGenerator(),
+		Email:     input.Email,
+		Name:      input.Name,
+		CreatedAt: s.now(),
+	}
+	if err := s.repo.Create(ctx, user); err != nil {
+		return model.User{}, err
+	}
+	return s.repo.Get(ctx, user.ID)
+}
+
+func (s *UserService) Get(ctx context.Context, id uuid.UUID) (model.User, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *UserService) List(ctx context.Context, filter repository.ListFilter) ([]model.User, error) {
+	return s.repo.List(ctx, filter)
+}
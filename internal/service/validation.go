@@ -2,44 +2,180 @@ package service
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/google/uuid"
+
 	"github.com/fuzail-ahmed/codex-test/internal/model"
 )
 
-func validateCreate(input CreateTodoInput) error {
-	if err := validateTitle(input.Title); err != nil {
-		return err
+const (
+	maxTitleLength       = 200
+	maxDescriptionLength = 2000
+	maxNameLength        = 200
+	maxEmailLength       = 254
+	maxGroupLength       = 100
+	maxAssignees         = 50
+)
+
+// Violation codes are stable, machine-readable reasons for a FieldViolation.
+const (
+	ViolationRequired   = "REQUIRED"
+	ViolationTooLong    = "TOO_LONG"
+	ViolationTooMany    = "TOO_MANY"
+	ViolationInvalid    = "INVALID"
+	ViolationDuplicate  = "DUPLICATE"
+	ViolationUnknown    = "UNKNOWN_REFERENCE"
+	ViolationOutOfRange = "OUT_OF_RANGE"
+)
+
+// FieldViolation describes why a single input field was rejected. Field is a
+// path using the request's wire names, e.g. "title" or "items[3].title".
+// Message completes a sentence that starts with the field name, and Limit is
+// the bound that was exceeded for TOO_LONG and TOO_MANY.
+type FieldViolation struct {
+	Field   string
+	Code    string
+	Message string
+	Limit   int
+}
+
+// ValidationError is an ErrValidation that lists every offending field, so
+// transports can point clients at the inputs to fix.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.Field + " " + v.Message
 	}
-	if err := validateDescription(input.Description); err != nil {
-		return err
+	return ErrValidation.Error() + ": " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// prefixed returns a copy with every field path nested under prefix.
+func (e *ValidationError) prefixed(prefix string) *ValidationError {
+	out := &ValidationError{Violations: make([]FieldViolation, len(e.Violations))}
+	for i, v := range e.Violations {
+		v.Field = prefix + "." + v.Field
+		out.Violations[i] = v
 	}
-	return nil
+	return out
 }
 
-func validateTitle(title string) error {
-	if strings.TrimSpace(title) == "" {
-		return wrapValidation("title is required")
+// violations accumulates field violations so a request reports all of its
+// problems at once rather than the first one.
+type violations []FieldViolation
+
+func (v *violations) add(field, code, message string) {
+	*v = append(*v, FieldViolation{Field: field, Code: code, Message: message})
+}
+
+func (v *violations) addLimit(field, code string, limit int) {
+	message := "must be at most " + strconv.Itoa(limit) + " characters"
+	if code == ViolationTooMany {
+		message = "must have at most " + strconv.Itoa(limit) + " entries"
 	}
-	if len(title) > 200 {
-		return wrapValidation("title too long")
+	*v = append(*v, FieldViolation{Field: field, Code: code, Message: message, Limit: limit})
+}
+
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
 	}
-	return nil
+	return &ValidationError{Violations: v}
+}
+
+func invalidField(field, code, message string) error {
+	var v violations
+	v.add(field, code, message)
+	return v.err()
 }
 
-func validateDescription(description string) error {
-	if len(description) > 2000 {
-		return wrapValidation("description too long")
+func itemPath(index int) string {
+	return "items[" + strconv.Itoa(index) + "]"
+}
+
+func validateCreate(input CreateTodoInput) violations {
+	var v violations
+	checkTitle(&v, input.Title)
+	checkDescription(&v, input.Description)
+	checkAssigneeList(&v, input.Assignees)
+	return v
+}
+
+func validateUpsert(input UpsertTodoInput) violations {
+	var v violations
+	checkTitle(&v, input.Title)
+	checkDescription(&v, input.Description)
+	checkStatus(&v, input.Status)
+	checkAssigneeList(&v, input.Assignees)
+	return v
+}
+
+func checkTitle(v *violations, title string) {
+	if strings.TrimSpace(title) == "" {
+		v.add("title", ViolationRequired, "is required")
+	} else if len(title) > maxTitleLength {
+		v.addLimit("title", ViolationTooLong, maxTitleLength)
 	}
-	return nil
 }
 
-func validateStatus(status model.Status) error {
+func checkDescription(v *violations, description string) {
+	if len(description) > maxDescriptionLength {
+		v.addLimit("description", ViolationTooLong, maxDescriptionLength)
+	}
+}
+
+func checkStatus(v *violations, status model.Status) {
 	switch status {
 	case model.StatusPending, model.StatusDone:
-		return nil
 	default:
-		return wrapValidation("invalid status")
+		v.add("status", ViolationInvalid, "must be pending or done")
+	}
+}
+
+func checkAssigneeList(v *violations, assignees []uuid.UUID) {
+	if len(assignees) > maxAssignees {
+		v.addLimit("assignees", ViolationTooMany, maxAssignees)
+		return
+	}
+	seen := make(map[uuid.UUID]struct{}, len(assignees))
+	for i, id := range assignees {
+		field := "assignees[" + strconv.Itoa(i) + "]"
+		if id == uuid.Nil {
+			v.add(field, ViolationInvalid, "must be a user id")
+			continue
+		}
+		if _, dup := seen[id]; dup {
+			v.add(field, ViolationDuplicate, "is listed more than once")
+			continue
+		}
+		seen[id] = struct{}{}
+	}
+}
+
+func checkEmail(v *violations, email string) {
+	if strings.TrimSpace(email) == "" {
+		v.add("email", ViolationRequired, "is required")
+	} else if len(email) > maxEmailLength {
+		v.addLimit("email", ViolationTooLong, maxEmailLength)
+	} else if !strings.Contains(email, "@") {
+		v.add("email", ViolationInvalid, "must be an email address")
+	}
+}
+
+func checkName(v *violations, name string) {
+	if strings.TrimSpace(name) == "" {
+		v.add("name", ViolationRequired, "is required")
+	} else if len(name) > maxNameLength {
+		v.addLimit("name", ViolationTooLong, maxNameLength)
 	}
 }
 
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/events"
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+// WatchFilter narrows a watch. Empty fields match everything.
+type WatchFilter struct {
+	Types      []events.Type
+	CreatedBy  uuid.UUID
+	AssignedTo uuid.UUID
+}
+
+// Watch subscribes the caller to changes of todos in its tenant that it may
+// view and that match filter. resumeToken replays events missed since the
+// token was received. The caller must Close the subscription.
+func (s *Service) Watch(ctx context.Context, filter WatchFilter, resumeToken string) (*events.Subscription, error) {
+	if s.events == nil {
+		return nil, wrapValidation("watching is not enabled")
+	}
+	for i, t := range filter.Types {
+		switch t {
+		case events.TypeCreated, events.TypeUpdated, events.TypeDeleted:
+		default:
+			return nil, invalidField("types["+strconv.Itoa(i)+"]", ViolationInvalid, "must be created, updated or deleted")
+		}
+	}
+	tenantID, err := tenant.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	principal, hasPrincipal := auth.PrincipalFromContext(ctx)
+	match := func(e events.Event) bool {
+		if e.Todo.TenantID != tenantID {
+			return false
+		}
+		if len(filter.Types) > 0 && !slices.Contains(filter.Types, e.Type) {
+			return false
+		}
+		if filter.CreatedBy != uuid.Nil && e.Todo.CreatedBy != filter.CreatedBy {
+			return false
+		}
+		if filter.AssignedTo != uuid.Nil && !slices.Contains(e.Todo.Assignees, filter.AssignedTo) {
+			return false
+		}
+		if s.acl == nil {
+			return true
+		}
+		return effectivePermission(e.Todo, principal, hasPrincipal, e.Grants).Includes(model.PermissionViewer)
+	}
+	return s.events.Subscribe(match, resumeToken)
+}
+
+// publish records changes to todos. Events carry the todo's grants so
+// watchers can be filtered by visibility.
+func (s *Service) publish(ctx context.Context, eventType events.Type, todos ...model.Todo) {
+	if s.events == nil || len(todos) == 0 {
+		return
+	}
+	batch := make([]events.Event, 0, len(todos))
+	for _, todo := range todos {
+		batch = append(batch, s.event(ctx, eventType, todo))
+	}
+	s.events.Publish(batch...)
+}
+
+// event builds an unpublished event. A failed grant lookup leaves Grants
+// empty, which only hides the event from users who see the todo through a
+// grant.
+func (s *Service) event(ctx context.Context, eventType events.Type, todo model.Todo) events.Event {
+	e := events.Event{Type: eventType, Todo: todo}
+	if s.acl != nil && eventType != events.TypeCreated {
+		e.Grants, _ = s.acl.Grants(ctx, todo.ID)
+	}
+	return e
+}
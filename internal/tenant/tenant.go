@@ -0,0 +1,48 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+const (
+	// Header carries the tenant ID on HTTP requests.
+	Header = "X-Tenant-ID"
+	// MetadataKey carries the tenant ID on gRPC requests.
+	MetadataKey = "x-tenant-id"
+)
+
+var (
+	ErrMissing = errors.New("tenant id is required")
+	ErrInvalid = errors.New("invalid tenant id")
+)
+
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,62}$`)
+
+type contextKey struct{}
+
+// WithID returns a context scoped to the given tenant.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant ID stored in ctx, or ErrMissing.
+func FromContext(ctx context.Context) (string, error) {
+	id, _ := ctx.Value(contextKey{}).(string)
+	if id == "" {
+		return "", ErrMissing
+	}
+	return id, nil
+}
+
+// Validate checks that id is a non-empty slug of at most 63 characters.
+func Validate(id string) error {
+	if id == "" {
+		return ErrMissing
+	}
+	if !idPattern.MatchString(id) {
+		return ErrInvalid
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+	todov1 "github.com/fuzail-ahmed/codex-test/shared/gen/todo/v1"
+)
+
+func (s *Server) ShareTodo(ctx context.Context, req *todov1.ShareTodoRequest) (*todov1.ShareTodoResponse, error) {
+	id, err := parseUUID(req.GetTodoId())
+	if err != nil {
+		return nil, err
+	}
+	grant, err := s.svc.Share(ctx, id, service.ShareTodoInput{
+		SubjectType: mapSubjectType(req.GetSubjectType()),
+		SubjectID:   req.GetSubjectId(),
+		Permission:  mapPermission(req.GetPermission()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &todov1.ShareTodoResponse{Grant: mapGrant(grant)}, nil
+}
+
+func (s *Server) UnshareTodo(ctx context.Context, req *todov1.UnshareTodoRequest) (*todov1.UnshareTodoResponse, error) {
+	id, err := parseUUID(req.GetTodoId())
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := s.svc.Unshare(ctx, id, mapSubjectType(req.GetSubjectType()), req.GetSubjectId())
+	if err != nil {
+		return nil, err
+	}
+	return &todov1.UnshareTodoResponse{Deleted: deleted}, nil
+}
+
+func (s *Server) ListTodoGrants(ctx context.Context, req *todov1.ListTodoGrantsRequest) (*todov1.ListTodoGrantsResponse, error) {
+	id, err := parseUUID(req.GetTodoId())
+	if err != nil {
+		return nil, err
+	}
+	grants, err := s.svc.Grants(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := &todov1.ListTodoGrantsResponse{Grants: make([]*todov1.Grant, 0, len(grants))}
+	for _, grant := range grants {
+		resp.Grants = append(resp.Grants, mapGrant(grant))
+	}
+	return resp, nil
+}
+
+func mapGrant(grant model.Grant) *todov1.Grant {
+	return &todov1.Grant{
+		TodoId:        grant.TodoID.String(),
+		SubjectType:   mapSubjectTypeToProto(grant.SubjectType),
+		SubjectId:     grant.SubjectID,
+		Permission:    mapPermissionToProto(grant.Permission),
+		CreatedAtUnix: grant.CreatedAt.Unix(),
+	}
+}
+
+// mapSubjectType and mapPermission return empty values for unspecified
+// enums so the service rejects them as invalid.
+func mapSubjectType(subjectType todov1.SubjectType) model.SubjectType {
+	switch subjectType {
+	case todov1.SubjectType_SUBJECT_TYPE_USER:
+		return model.SubjectUser
+	case todov1.SubjectType_SUBJECT_TYPE_GROUP:
+		return model.SubjectGroup
+	default:
+		return ""
+	}
+}
+
+func mapSubjectTypeToProto(subjectType model.SubjectType) todov1.SubjectType {
+	switch subjectType {
+	case model.SubjectUser:
+		return todov1.SubjectType_SUBJECT_TYPE_USER
+	case model.SubjectGroup:
+		return todov1.SubjectType_SUBJECT_TYPE_GROUP
+	default:
+		return todov1.SubjectType_SUBJECT_TYPE_UNSPECIFIED
+	}
+}
+
+func mapPermission(permission todov1.Permission) model.Permission {
+	switch permission {
+	case todov1.Permission_PERMISSION_VIEWER:
+		return model.PermissionViewer
+	case todov1.Permission_PERMISSION_EDITOR:
+		return model.PermissionEditor
+	case todov1.Permission_PERMISSION_OWNER:
+		return model.PermissionOwner
+	default:
+		return ""
+	}
+}
+
+func mapPermissionToProto(permission model.Permission) todov1.Permission {
+	switch permission {
+	case model.PermissionViewer:
+		return todov1.Permission_PERMISSION_VIEWER
+	case model.PermissionEditor:
+		return todov1.Permission_PERMISSION_EDITOR
+	case model.PermissionOwner:
+		return todov1.Permission_PERMISSION_OWNER
+	default:
+		return todov1.Permission_PERMISSION_UNSPECIFIED
+	}
+}
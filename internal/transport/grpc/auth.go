@@ -0,0 +1,82 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+// UnaryAuthInterceptor verifies bearer tokens in the authorization metadata
+// and puts the resulting principal and its tenant into the context. Pass it
+// to ListenAndServe through grpc.ChainUnaryInterceptor so it runs before the
+// built-in interceptors. Calls without a token continue anonymously; an
+// x-tenant-id naming another tenant is rejected with PERMISSION_DENIED.
+func UnaryAuthInterceptor(verifier auth.Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, verifier)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor is the streaming counterpart of UnaryAuthInterceptor.
+func StreamAuthInterceptor(verifier auth.Verifier) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), verifier)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate drops the x-user-id metadata so it cannot be used to
+// impersonate another user, then verifies the bearer token if present. Calls
+// already authenticated by an API key are left alone.
+func authenticate(ctx context.Context, verifier auth.Verifier) (context.Context, error) {
+	if _, ok := auth.PrincipalFromContext(ctx); ok {
+		return ctx, nil
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+	md = md.Copy()
+	md.Delete(auth.UserMetadataKey)
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ctx, nil
+	}
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, status.Error(codes.Unauthenticated, "authorization metadata must be a bearer token")
+	}
+	principal, tenantID, err := verifier.Verify(strings.TrimSpace(token))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	if values := md.Get(tenant.MetadataKey); len(values) > 0 && values[0] != tenantID {
+		return nil, status.Error(codes.PermissionDenied, "token belongs to another tenant")
+	}
+	return tenant.WithID(auth.WithPrincipal(ctx, principal), tenantID), nil
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
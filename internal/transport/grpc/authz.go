@@ -0,0 +1,94 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/fuzail-ahmed/codex-test/internal/authz"
+	todov1 "github.com/fuzail-ahmed/codex-test/shared/gen/todo/v1"
+	todov2 "github.com/fuzail-ahmed/codex-test/shared/gen/todo/v2"
+)
+
+var methodActions = map[string]authz.Action{
+	todov1.TodoService_CreateTodo_FullMethodName:      authz.ActionTodoCreate,
+	todov1.TodoService_BulkCreateTodos_FullMethodName: authz.ActionTodoBulkCreate,
+	todov1.TodoService_GetTodo_FullMethodName:         authz.ActionTodoRead,
+	todov1.TodoService_ListTodos_FullMethodName:       authz.ActionTodoRead,
+	todov1.TodoService_UpdateTodo_FullMethodName:      authz.ActionTodoUpdate,
+	todov1.TodoService_UpsertTodo_FullMethodName:      authz.ActionTodoUpdate,
+	todov1.TodoService_DeleteTodo_FullMethodName:      authz.ActionTodoDelete,
+	todov1.TodoService_ShareTodo_FullMethodName:       authz.ActionTodoShare,
+	todov1.TodoService_UnshareTodo_FullMethodName:     authz.ActionTodoShare,
+	todov1.TodoService_ListTodoGrants_FullMethodName:  authz.ActionTodoRead,
+	todov1.TodoService_WatchTodos_FullMethodName:      authz.ActionTodoRead,
+	todov1.TodoService_ImportTodos_FullMethodName:     authz.ActionTodoBulkCreate,
+	todov1.TodoService_ExportTodos_FullMethodName:     authz.ActionTodoRead,
+	todov1.UserService_CreateUser_FullMethodName:      authz.ActionUserCreate,
+	todov1.UserService_GetUser_FullMethodName:         authz.ActionUserRead,
+	todov1.UserService_ListUsers_FullMethodName:       authz.ActionUserRead,
+
+	todov2.TodoService_CreateTodo_FullMethodName:       authz.ActionTodoCreate,
+	todov2.TodoService_BatchCreateTodos_FullMethodName: authz.ActionTodoBulkCreate,
+	todov2.TodoService_GetTodo_FullMethodName:          authz.ActionTodoRead,
+	todov2.TodoService_ListTodos_FullMethodName:        authz.ActionTodoRead,
+	todov2.TodoService_UpdateTodo_FullMethodName:       authz.ActionTodoUpdate,
+	todov2.TodoService_DeleteTodo_FullMethodName:       authz.ActionTodoDelete,
+}
+
+// servicePrefixActions guard whole services registered outside this package.
+// Reflection and channelz describe the API and its connections, so they are
+// limited to operators.
+var servicePrefixActions = map[string]authz.Action{
+	"/grpc.reflection.":  authz.ActionAdminDebug,
+	"/grpc.channelz.v1.": authz.ActionAdminDebug,
+}
+
+// unaryAuthzInterceptor checks the action mapped to the called method. It
+// fails closed: a method without a rule is always denied. Health checks are
+// always allowed.
+func unaryAuthzInterceptor(authorizer authz.Authorizer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkMethod(ctx, authorizer, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamAuthzInterceptor is the streaming counterpart of
+// unaryAuthzInterceptor.
+func streamAuthzInterceptor(authorizer authz.Authorizer) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkMethod(ss.Context(), authorizer, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func checkMethod(ctx context.Context, authorizer authz.Authorizer, method string) error {
+	if isHealthCheck(method) {
+		return nil
+	}
+	action, ok := methodAction(method)
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "no authorization rule for %s", method)
+	}
+	return authz.Check(ctx, authorizer, action)
+}
+
+func methodAction(method string) (authz.Action, bool) {
+	if action, ok := methodActions[method]; ok {
+		return action, true
+	}
+	for prefix, action := range servicePrefixActions {
+		if strings.HasPrefix(method, prefix) {
+			return action, true
+		}
+	}
+	return "", false
+}
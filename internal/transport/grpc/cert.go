@@ -0,0 +1,70 @@
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+// UnaryClientCertInterceptor uses a verified TLS client certificate as the
+// caller's identity when no API key or bearer token already provided one,
+// and the certificate's organization as its tenant. Pass it through opts
+// after the API key and bearer token interceptors. The x-user-id metadata
+// is dropped whether or not the caller sent a certificate.
+func UnaryClientCertInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := withClientCert(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamClientCertInterceptor is the streaming counterpart of
+// UnaryClientCertInterceptor.
+func StreamClientCertInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := withClientCert(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func withClientCert(ctx context.Context) (context.Context, error) {
+	if _, ok := auth.PrincipalFromContext(ctx); ok {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	md.Delete(auth.UserMetadataKey)
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 {
+		return ctx, nil
+	}
+	principal, tenantID := auth.PrincipalFromCertificate(tlsInfo.State.VerifiedChains[0][0])
+	ctx = auth.WithPrincipal(ctx, principal)
+	if tenantID == "" {
+		return ctx, nil
+	}
+	if values := md.Get(tenant.MetadataKey); len(values) > 0 && values[0] != tenantID {
+		return nil, status.Error(codes.PermissionDenied, "certificate belongs to another tenant")
+	}
+	return tenant.WithID(ctx, tenantID), nil
+}
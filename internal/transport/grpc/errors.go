@@ -0,0 +1,120 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/fuzail-ahmed/codex-test/internal/errcode"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+)
+
+// unaryErrorInterceptor converts errors returned by handlers into gRPC
+// status errors so clients receive precise codes.
+func unaryErrorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		logInternal(ctx, grpc.SetHeader, err)
+		return nil, toStatusError(err)
+	}
+	return resp, nil
+}
+
+// streamErrorInterceptor is the streaming counterpart of
+// unaryErrorInterceptor.
+func streamErrorInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, ss); err != nil {
+		logInternal(ss.Context(), func(_ context.Context, md metadata.MD) error { return ss.SetHeader(md) }, err)
+		return toStatusError(err)
+	}
+	return nil
+}
+
+// requestIDKey carries the request ID, as X-Request-ID does over HTTP.
+const requestIDKey = "x-request-id"
+
+// logInternal logs an error that toStatusError would report as INTERNAL,
+// since the status drops it. The log line is tagged with the caller's
+// x-request-id, or a new one sent back in the response headers, so a
+// failure can be matched to server logs.
+func logInternal(ctx context.Context, setHeader func(context.Context, metadata.MD) error, err error) {
+	if _, ok := status.FromError(err); ok {
+		return
+	}
+	if code, _ := errcode.Of(err); code != errcode.Internal {
+		return
+	}
+	var id string
+	if ids := metadata.ValueFromIncomingContext(ctx, requestIDKey); len(ids) > 0 {
+		id = ids[0]
+	}
+	if id == "" {
+		id = uuid.NewString()
+		_ = setHeader(ctx, metadata.Pairs(requestIDKey, id))
+	}
+	log.Printf("request %s: internal error: %v", id, err)
+}
+
+// retryDelay is the back-off suggested to clients for transient failures
+// that do not carry their own.
+const retryDelay = time.Second
+
+// toStatusError translates service and repository errors to gRPC statuses
+// using the shared errcode catalog. Every status carries an ErrorInfo whose
+// reason is the catalog code, plus BadRequest, QuotaFailure or RetryInfo
+// where they apply. Errors that already carry a status pass through.
+func toStatusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	code, message := errcode.Of(err)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: string(code), Domain: errcode.Domain}}
+	var validation *service.ValidationError
+	var unavailable *repository.UnavailableError
+	switch {
+	case errors.As(err, &validation):
+		details = append(details, badRequest(validation))
+	case code == errcode.QuotaExceeded:
+		details = append(details, &errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{{Subject: "tenant", Description: message}},
+		})
+	case errors.As(err, &unavailable) && unavailable.RetryAfter > 0:
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(unavailable.RetryAfter)})
+	case code == errcode.ConcurrentUpdate, code == errcode.Unavailable:
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(retryDelay)})
+	}
+	return withDetails(errcode.Lookup(code).GRPCCode, message, details...)
+}
+
+// badRequest reports each field violation so clients can map the failure
+// back to their inputs.
+func badRequest(err *service.ValidationError) *errdetails.BadRequest {
+	details := &errdetails.BadRequest{}
+	for _, v := range err.Violations {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Field + " " + v.Message,
+			Reason:      v.Code,
+		})
+	}
+	return details
+}
+
+func withDetails(code codes.Code, message string, details ...protoadapt.MessageV1) error {
+	st, err := status.New(code, message).WithDetails(details...)
+	if err != nil {
+		return status.Error(code, message)
+	}
+	return st.Err()
+}
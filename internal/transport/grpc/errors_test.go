@@ -0,0 +1,73 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/fuzail-ahmed/codex-test/internal/errcode"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+)
+
+func TestToStatusErrorCodes(t *testing.T) {
+	cases := []struct {
+		err  error
+		want codes.Code
+	}{
+		{fmt.Errorf("get: %w", repository.ErrNotFound), codes.NotFound},
+		{repository.ErrConflict, codes.AlreadyExists},
+		{repository.ErrSerialization, codes.Aborted},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{fmt.Errorf("list: %w", context.Canceled), codes.Canceled},
+		{service.ErrPermissionDenied, codes.PermissionDenied},
+		{fmt.Errorf("boom"), codes.Internal},
+	}
+	for _, tc := range cases {
+		if got := status.Code(toStatusError(tc.err)); got != tc.want {
+			t.Errorf("%v: expected %s, got %s", tc.err, tc.want, got)
+		}
+	}
+}
+
+func TestToStatusErrorValidationDetails(t *testing.T) {
+	_, _, err := service.New(nil, 1).Upsert(context.Background(), uuid.Nil, service.UpsertTodoInput{})
+	st := status.Convert(toStatusError(err))
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %s", st.Code())
+	}
+	if len(st.Details()) != 2 {
+		t.Fatalf("expected two details, got %d", len(st.Details()))
+	}
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	if !ok || info.Reason != string(errcode.ValidationFailed) || info.Domain != errcode.Domain {
+		t.Fatalf("expected VALIDATION_FAILED error info, got %v", st.Details()[0])
+	}
+	badRequest, ok := st.Details()[1].(*errdetails.BadRequest)
+	if !ok || len(badRequest.FieldViolations) != 1 || badRequest.FieldViolations[0].Field != "id" {
+		t.Fatalf("expected id field violation, got %v", st.Details()[1])
+	}
+}
+
+func TestMapBulkResultsCarriesStatus(t *testing.T) {
+	_, _, err := service.New(nil, 1).Upsert(context.Background(), uuid.Nil, service.UpsertTodoInput{})
+	resp := mapBulkResults([]service.BulkItemResult{{Index: 3, Err: err}})
+	if len(resp.Results) != 1 {
+		t.Fatalf("expected one result, got %d", len(resp.Results))
+	}
+	st := status.FromProto(resp.Results[0].GetStatus())
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %s", st.Code())
+	}
+	if len(st.Details()) != 2 {
+		t.Fatalf("expected ErrorInfo and BadRequest details, got %v", st.Details())
+	}
+	if _, ok := st.Details()[1].(*errdetails.BadRequest); !ok {
+		t.Fatalf("expected BadRequest, got %v", st.Details()[1])
+	}
+}
@@ -0,0 +1,90 @@
+package grpcserver
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthPrefix is the health checking service. It reveals nothing but
+// serving status, so it is the only service that bypasses the tenant and
+// authorization interceptors.
+const healthPrefix = "/grpc.health.v1."
+
+func isHealthCheck(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, healthPrefix)
+}
+
+// Health serves grpc.health.v1 for the whole server ("") and for every
+// service registered on it. Everything reports NOT_SERVING until the first
+// check passes.
+type Health struct {
+	server   *health.Server
+	services []string
+}
+
+// RegisterHealth adds the health service to server. Call it after the other
+// services are registered so they are all covered.
+func RegisterHealth(server *grpc.Server) *Health {
+	h := &Health{server: health.NewServer(), services: []string{""}}
+	for name := range server.GetServiceInfo() {
+		h.services = append(h.services, name)
+	}
+	h.set(healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, h.server)
+	return h
+}
+
+// Watch runs check immediately and then every interval until ctx is done,
+// reporting SERVING while it succeeds. Each check gets interval to finish.
+func (h *Health) Watch(ctx context.Context, interval time.Duration, check func(context.Context) error) {
+	run := func() {
+		checkCtx, cancel := context.WithTimeout(ctx, interval)
+		defer cancel()
+		if err := check(checkCtx); err != nil {
+			if ctx.Err() == nil {
+				log.Printf("grpc health check failed: %v", err)
+			}
+			h.set(healthpb.HealthCheckResponse_NOT_SERVING)
+			return
+		}
+		h.set(healthpb.HealthCheckResponse_SERVING)
+	}
+	run()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				run()
+			}
+		}
+	}()
+}
+
+// Serving reports whether the server as a whole is SERVING, for probes
+// that cannot speak gRPC.
+func (h *Health) Serving(ctx context.Context) bool {
+	resp, err := h.server.Check(ctx, &healthpb.HealthCheckRequest{})
+	return err == nil && resp.GetStatus() == healthpb.HealthCheckResponse_SERVING
+}
+
+// Shutdown reports NOT_SERVING for good, so probes and load balancers stop
+// sending traffic while in-flight calls drain.
+func (h *Health) Shutdown() {
+	h.server.Shutdown()
+}
+
+func (h *Health) set(status healthpb.HealthCheckResponse_ServingStatus) {
+	for _, service := range h.services {
+		h.server.SetServingStatus(service, status)
+	}
+}
@@ -0,0 +1,117 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/fuzail-ahmed/codex-test/internal/authz"
+	"github.com/fuzail-ahmed/codex-test/internal/repository/memory"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+	todov1 "github.com/fuzail-ahmed/codex-test/shared/gen/todo/v1"
+)
+
+func TestHealthFollowsCheckWithoutTenantOrAuthorization(t *testing.T) {
+	// An empty policy denies every action.
+	srv, lis, err := ListenAndServe("127.0.0.1:0", service.New(memory.New(), 1), nil, &authz.Policy{})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var down atomic.Bool
+	health := RegisterHealth(srv)
+	health.Watch(ctx, 10*time.Millisecond, func(context.Context) error {
+		if down.Load() {
+			return errors.New("database unreachable")
+		}
+		return nil
+	})
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+	expect := func(service string, want healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for {
+			resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+			if err == nil && resp.GetStatus() == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %q to be %s, got %v %v", service, want, resp.GetStatus(), err)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	expect("", healthpb.HealthCheckResponse_SERVING)
+	expect(todov1.TodoService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	down.Store(true)
+	expect("", healthpb.HealthCheckResponse_NOT_SERVING)
+	down.Store(false)
+	expect("", healthpb.HealthCheckResponse_SERVING)
+	health.Shutdown()
+	expect(todov1.TodoService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+func TestReflectionRequiresAdminAction(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy *authz.Policy
+		want   codes.Code
+	}{
+		{"denied", &authz.Policy{AnonymousRole: "member", Roles: map[string][]string{"member": {"todo:*"}}}, codes.PermissionDenied},
+		{"allowed", &authz.Policy{AnonymousRole: "ops", Roles: map[string][]string{"ops": {"admin:debug"}}}, codes.OK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, lis, err := ListenAndServe("127.0.0.1:0", service.New(memory.New(), 1), nil, tc.policy)
+			if err != nil {
+				t.Fatalf("listen: %v", err)
+			}
+			reflection.Register(srv)
+			go func() { _ = srv.Serve(lis) }()
+			defer srv.Stop()
+
+			conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				t.Fatalf("dial: %v", err)
+			}
+			defer conn.Close()
+			ctx := metadata.AppendToOutgoingContext(context.Background(), tenant.MetadataKey, "acme")
+			stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+			if err != nil {
+				t.Fatalf("open stream: %v", err)
+			}
+			req := &reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+			}
+			if err := stream.Send(req); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+			_, err = stream.Recv()
+			if got := status.Code(err); got != tc.want {
+				t.Fatalf("expected %s, got %v", tc.want, err)
+			}
+		})
+	}
+}
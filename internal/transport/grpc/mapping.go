@@ -1,25 +1,60 @@
 package grpcserver
 
 import (
-	"fmt"
+	"context"
+	"strconv"
 
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/fuzail-ahmed/codex-test/internal/errcode"
+	"github.com/fuzail-ahmed/codex-test/internal/events"
 	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
 	todov1 "github.com/fuzail-ahmed/codex-test/shared/gen/todo/v1"
 )
 
 func mapTodo(todo model.Todo) *todov1.Todo {
 	return &todov1.Todo{
 		Id:            todo.ID.String(),
+		TenantId:      todo.TenantID,
 		Title:         todo.Title,
 		Description:   todo.Description,
 		Status:        mapStatusToProto(todo.Status),
 		CreatedAtUnix: todo.CreatedAt.Unix(),
 		UpdatedAtUnix: todo.UpdatedAt.Unix(),
+		CreatedBy:     mapOptionalUUID(todo.CreatedBy),
+		Assignees:     mapUUIDs(todo.Assignees),
 	}
 }
 
+func mapUser(user model.User) *todov1.User {
+	return &todov1.User{
+		Id:            user.ID.String(),
+		TenantId:      user.TenantID,
+		Email:         user.Email,
+		Name:          user.Name,
+		CreatedAtUnix: user.CreatedAt.Unix(),
+	}
+}
+
+func mapOptionalUUID(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+func mapUUIDs(ids []uuid.UUID) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, id.String())
+	}
+	return out
+}
+
 func mapTodos(todos []model.Todo) []*todov1.Todo {
 	items := make([]*todov1.Todo, 0, len(todos))
 	for _, todo := range todos {
@@ -28,6 +63,93 @@ func mapTodos(todos []model.Todo) []*todov1.Todo {
 	return items
 }
 
+func mapBulkResults(results []service.BulkItemResult) *todov1.BulkCreateTodosResponse {
+	resp := &todov1.BulkCreateTodosResponse{
+		Todos:   make([]*todov1.Todo, 0, len(results)),
+		Results: make([]*todov1.BulkCreateResult, 0, len(results)),
+	}
+	for _, result := range results {
+		item := &todov1.BulkCreateResult{Index: int32(result.Index)}
+		if result.Err != nil {
+			st := status.Convert(toStatusError(result.Err))
+			item.Status = st.Proto()
+			item.Error = st.Message()
+		} else {
+			item.Todo = mapTodo(result.Todo)
+			resp.Todos = append(resp.Todos, item.Todo)
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	return resp
+}
+
+func mapImportResult(result service.ImportResult) *todov1.ImportTodosResponse {
+	resp := &todov1.ImportTodosResponse{
+		Created: int64(result.Created),
+		Failed:  int64(result.Failed),
+		Errors:  make([]*todov1.ImportError, 0, len(result.Errors)),
+	}
+	for _, lineErr := range result.Errors {
+		code, message := errcode.Of(lineErr.Err)
+		if st, ok := status.FromError(lineErr.Err); ok {
+			code, message = errcode.InvalidArgument, st.Message()
+		}
+		resp.Errors = append(resp.Errors, &todov1.ImportError{
+			Line:    int64(lineErr.Line),
+			Code:    string(code),
+			Message: message,
+		})
+	}
+	return resp
+}
+
+func mapEvent(e events.Event) *todov1.TodoEvent {
+	return &todov1.TodoEvent{
+		Type:           mapEventTypeToProto(e.Type),
+		Todo:           mapTodo(e.Todo),
+		ResumeToken:    e.Token(),
+		OccurredAtUnix: e.Time.Unix(),
+	}
+}
+
+func mapEventTypeToProto(t events.Type) todov1.TodoEventType {
+	switch t {
+	case events.TypeCreated:
+		return todov1.TodoEventType_TODO_EVENT_TYPE_CREATED
+	case events.TypeUpdated:
+		return todov1.TodoEventType_TODO_EVENT_TYPE_UPDATED
+	case events.TypeDeleted:
+		return todov1.TodoEventType_TODO_EVENT_TYPE_DELETED
+	default:
+		return todov1.TodoEventType_TODO_EVENT_TYPE_UNSPECIFIED
+	}
+}
+
+// watchFilter resolves the filters of a WatchTodos request.
+func watchFilter(ctx context.Context, req *todov1.WatchTodosRequest) (service.WatchFilter, error) {
+	var filter service.WatchFilter
+	for i, t := range req.GetTypes() {
+		switch t {
+		case todov1.TodoEventType_TODO_EVENT_TYPE_CREATED:
+			filter.Types = append(filter.Types, events.TypeCreated)
+		case todov1.TodoEventType_TODO_EVENT_TYPE_UPDATED:
+			filter.Types = append(filter.Types, events.TypeUpdated)
+		case todov1.TodoEventType_TODO_EVENT_TYPE_DELETED:
+			filter.Types = append(filter.Types, events.TypeDeleted)
+		default:
+			return filter, maskViolation("types["+strconv.Itoa(i)+"]", "must be a specific event type")
+		}
+	}
+	var err error
+	if filter.CreatedBy, err = parseUserFilter(ctx, req.GetCreatedBy()); err != nil {
+		return filter, err
+	}
+	if filter.AssignedTo, err = parseUserFilter(ctx, req.GetAssignedTo()); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
 func mapStatusToProto(status model.Status) todov1.Status {
 	switch status {
 	case model.StatusPending:
@@ -50,10 +172,128 @@ func mapStatus(status todov1.Status) model.Status {
 	}
 }
 
+func parseOptionalUUID(value string) (uuid.UUID, error) {
+	if value == "" {
+		return uuid.Nil, nil
+	}
+	return parseUUID(value)
+}
+
 func parseUUID(value string) (uuid.UUID, error) {
 	id, err := uuid.Parse(value)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("invalid id")
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid id")
 	}
 	return id, nil
 }
+
+func parseUUIDs(values []string) ([]uuid.UUID, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		id, err := parseUUID(value)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// createInput parses the version-independent fields of a create request.
+func createInput(id, title, description string, assignees []string) (service.CreateTodoInput, error) {
+	todoID, err := parseOptionalUUID(id)
+	if err != nil {
+		return service.CreateTodoInput{}, err
+	}
+	assigneeIDs, err := parseUUIDs(assignees)
+	if err != nil {
+		return service.CreateTodoInput{}, err
+	}
+	return service.CreateTodoInput{
+		ID:          todoID,
+		Title:       title,
+		Description: description,
+		Assignees:   assigneeIDs,
+	}, nil
+}
+
+// listFilter resolves the user filters shared by every ListTodos version.
+func listFilter(ctx context.Context, limit, offset int, createdBy, assignedTo string) (repository.ListFilter, error) {
+	createdByID, err := parseUserFilter(ctx, createdBy)
+	if err != nil {
+		return repository.ListFilter{}, err
+	}
+	assignedToID, err := parseUserFilter(ctx, assignedTo)
+	if err != nil {
+		return repository.ListFilter{}, err
+	}
+	return repository.ListFilter{
+		Limit:      limit,
+		Offset:     offset,
+		CreatedBy:  createdByID,
+		AssignedTo: assignedToID,
+	}, nil
+}
+
+// todoFields is the version-independent content of a todo in an update
+// request. An empty status stands for STATUS_UNSPECIFIED.
+type todoFields struct {
+	title       string
+	description string
+	status      model.Status
+	assignees   []string
+}
+
+// updateInput builds an update that writes exactly the fields named in
+// paths, so empty values clear a field instead of being ignored.
+func (f todoFields) updateInput(paths []string) (service.UpdateTodoInput, error) {
+	var input service.UpdateTodoInput
+	for _, path := range paths {
+		switch path {
+		case "title":
+			input.Title = &f.title
+		case "description":
+			input.Description = &f.description
+		case "status":
+			input.Status = &f.status
+		case "assignees":
+			assignees, err := parseUUIDs(f.assignees)
+			if err != nil {
+				return input, err
+			}
+			if assignees == nil {
+				assignees = []uuid.UUID{}
+			}
+			input.Assignees = &assignees
+		default:
+			return input, maskViolation("update_mask", "contains unknown path \""+path+"\"")
+		}
+	}
+	return input, nil
+}
+
+// updateInputFromMask applies a v1 update mask.
+func updateInputFromMask(req *todov1.UpdateTodoRequest) (service.UpdateTodoInput, error) {
+	if req.GetClearAssignees() {
+		return service.UpdateTodoInput{}, maskViolation("clear_assignees", "must not be combined with update_mask")
+	}
+	fields := todoFields{
+		title:       req.GetTitle(),
+		description: req.GetDescription(),
+		assignees:   req.GetAssignees(),
+	}
+	if req.GetStatus() != todov1.Status_STATUS_UNSPECIFIED {
+		fields.status = mapStatus(req.GetStatus())
+	}
+	return fields.updateInput(req.GetUpdateMask().GetPaths())
+}
+
+// maskViolation reports a request field the transport itself rejected.
+func maskViolation(field, message string) error {
+	return &service.ValidationError{Violations: []service.FieldViolation{
+		{Field: field, Code: service.ViolationInvalid, Message: message},
+	}}
+}
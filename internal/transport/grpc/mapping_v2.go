@@ -0,0 +1,54 @@
+package grpcserver
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	todov2 "github.com/fuzail-ahmed/codex-test/shared/gen/todo/v2"
+)
+
+func mapTodoV2(todo model.Todo) *todov2.Todo {
+	return &todov2.Todo{
+		Id:          todo.ID.String(),
+		TenantId:    todo.TenantID,
+		Title:       todo.Title,
+		Description: todo.Description,
+		Status:      mapStatusToProtoV2(todo.Status),
+		CreatedBy:   mapOptionalUUID(todo.CreatedBy),
+		Assignees:   mapUUIDs(todo.Assignees),
+		CreateTime:  timestamppb.New(todo.CreatedAt),
+		UpdateTime:  timestamppb.New(todo.UpdatedAt),
+	}
+}
+
+func mapTodosV2(todos []model.Todo) []*todov2.Todo {
+	items := make([]*todov2.Todo, 0, len(todos))
+	for _, todo := range todos {
+		items = append(items, mapTodoV2(todo))
+	}
+	return items
+}
+
+func mapStatusToProtoV2(status model.Status) todov2.Status {
+	switch status {
+	case model.StatusPending:
+		return todov2.Status_STATUS_PENDING
+	case model.StatusDone:
+		return todov2.Status_STATUS_DONE
+	default:
+		return todov2.Status_STATUS_UNSPECIFIED
+	}
+}
+
+// mapStatusV2 maps STATUS_UNSPECIFIED to the empty status, which the service
+// rejects, rather than silently defaulting it.
+func mapStatusV2(status todov2.Status) model.Status {
+	switch status {
+	case todov2.Status_STATUS_PENDING:
+		return model.StatusPending
+	case todov2.Status_STATUS_DONE:
+		return model.StatusDone
+	default:
+		return ""
+	}
+}
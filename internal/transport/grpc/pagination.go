@@ -0,0 +1,86 @@
+package grpcserver
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+
+	pageTokenVersion = "k1"
+)
+
+// pageSize applies the default and cap that the repositories use.
+func pageSize(requested int32) int {
+	switch {
+	case requested <= 0:
+		return defaultPageSize
+	case requested > maxPageSize:
+		return maxPageSize
+	default:
+		return int(requested)
+	}
+}
+
+// filterHash identifies the resolved filters a token was issued for, so a
+// token cannot be replayed against different ones.
+func filterHash(filter repository.ListFilter) string {
+	sum := sha256.Sum256([]byte(filter.CreatedBy.String() + "/" + filter.AssignedTo.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// encodePageToken returns a token for the page after last, the final todo
+// of the current page. It is a keyset cursor on (created_at, id), the list
+// order, plus the hash of the filters.
+func encodePageToken(last model.Todo, filter repository.ListFilter) string {
+	raw := strings.Join([]string{
+		pageTokenVersion,
+		filterHash(filter),
+		strconv.FormatInt(last.CreatedAt.UnixNano(), 10),
+		last.ID.String(),
+	}, ":")
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodePageToken returns the position a token resumes after; an empty
+// token is the first page. Tokens issued for other filters are rejected.
+func decodePageToken(token string, filter repository.ListFilter) (*repository.Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalidPageToken()
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 4 || parts[0] != pageTokenVersion {
+		return nil, invalidPageToken()
+	}
+	if parts[1] != filterHash(filter) {
+		return nil, maskViolation("page_token", "was issued for different filters")
+	}
+	nanos, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, invalidPageToken()
+	}
+	id, err := uuid.Parse(parts[3])
+	if err != nil {
+		return nil, invalidPageToken()
+	}
+	return &repository.Cursor{CreatedAt: time.Unix(0, nanos), ID: id}, nil
+}
+
+func invalidPageToken() error {
+	return maskViolation("page_token", "is not a token returned by a previous call")
+}
@@ -4,13 +4,23 @@ import (
 	"context"
 	"net"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
-	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/authz"
+	"github.com/fuzail-ahmed/codex-test/internal/model"
 	"github.com/fuzail-ahmed/codex-test/internal/service"
 	todov1 "github.com/fuzail-ahmed/codex-test/shared/gen/todo/v1"
+	todov2 "github.com/fuzail-ahmed/codex-test/shared/gen/todo/v2"
 )
 
+// IdempotencyKeyMetadata is the metadata key clients use to make CreateTodo and
+// BulkCreateTodos safe to retry.
+const IdempotencyKeyMetadata = "idempotency-key"
+
 type Server struct {
 	todov1.UnimplementedTodoServiceServer
 	svc *service.Service
@@ -21,7 +31,11 @@ func New(svc *service.Service) *Server {
 }
 
 func (s *Server) CreateTodo(ctx context.Context, req *todov1.CreateTodoRequest) (*todov1.CreateTodoResponse, error) {
-	todo, err := s.svc.Create(ctx, service.CreateTodoInput{Title: req.GetTitle(), Description: req.GetDescription()})
+	input, err := createInput(req.GetId(), req.GetTitle(), req.GetDescription(), req.GetAssignees())
+	if err != nil {
+		return nil, err
+	}
+	todo, err := s.svc.Create(withIdempotencyKey(ctx), input)
 	if err != nil {
 		return nil, err
 	}
@@ -31,7 +45,19 @@ func (s *Server) CreateTodo(ctx context.Context, req *todov1.CreateTodoRequest)
 func (s *Server) BulkCreateTodos(ctx context.Context, req *todov1.BulkCreateTodosRequest) (*todov1.BulkCreateTodosResponse, error) {
 	inputs := make([]service.CreateTodoInput, 0, len(req.GetItems()))
 	for _, item := range req.GetItems() {
-		inputs = append(inputs, service.CreateTodoInput{Title: item.GetTitle(), Description: item.GetDescription()})
+		input, err := createInput(item.GetId(), item.GetTitle(), item.GetDescription(), item.GetAssignees())
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, input)
+	}
+	ctx = withIdempotencyKey(ctx)
+	if req.GetPartialSuccess() {
+		results, err := s.svc.BulkCreatePartial(ctx, inputs)
+		if err != nil {
+			return nil, err
+		}
+		return mapBulkResults(results), nil
 	}
 	todos, err := s.svc.BulkCreate(ctx, inputs)
 	if err != nil {
@@ -53,7 +79,11 @@ func (s *Server) GetTodo(ctx context.Context, req *todov1.GetTodoRequest) (*todo
 }
 
 func (s *Server) ListTodos(ctx context.Context, req *todov1.ListTodosRequest) (*todov1.ListTodosResponse, error) {
-	todos, err := s.svc.List(ctx, repository.ListFilter{Limit: int(req.GetLimit()), Offset: int(req.GetOffset())})
+	filter, err := listFilter(ctx, int(req.GetLimit()), int(req.GetOffset()), req.GetCreatedBy(), req.GetAssignedTo())
+	if err != nil {
+		return nil, err
+	}
+	todos, err := s.svc.List(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -65,6 +95,17 @@ func (s *Server) UpdateTodo(ctx context.Context, req *todov1.UpdateTodoRequest)
 	if err != nil {
 		return nil, err
 	}
+	if req.GetUpdateMask() != nil {
+		input, err := updateInputFromMask(req)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := s.svc.Update(ctx, id, input)
+		if err != nil {
+			return nil, err
+		}
+		return &todov1.UpdateTodoResponse{Todo: mapTodo(updated)}, nil
+	}
 	input := service.UpdateTodoInput{}
 	if req.Title != "" {
 		input.Title = &req.Title
@@ -76,6 +117,18 @@ func (s *Server) UpdateTodo(ctx context.Context, req *todov1.UpdateTodoRequest)
 		status := mapStatus(req.Status)
 		input.Status = &status
 	}
+	if req.GetClearAssignees() {
+		if len(req.GetAssignees()) > 0 {
+			return nil, status.Error(codes.InvalidArgument, "assignees and clear_assignees are mutually exclusive")
+		}
+		input.Assignees = &[]uuid.UUID{}
+	} else if len(req.GetAssignees()) > 0 {
+		assignees, err := parseUUIDs(req.GetAssignees())
+		if err != nil {
+			return nil, err
+		}
+		input.Assignees = &assignees
+	}
 	updated, err := s.svc.Update(ctx, id, input)
 	if err != nil {
 		return nil, err
@@ -83,6 +136,26 @@ func (s *Server) UpdateTodo(ctx context.Context, req *todov1.UpdateTodoRequest)
 	return &todov1.UpdateTodoResponse{Todo: mapTodo(updated)}, nil
 }
 
+func (s *Server) UpsertTodo(ctx context.Context, req *todov1.UpsertTodoRequest) (*todov1.UpsertTodoResponse, error) {
+	id, err := parseUUID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	assignees, err := parseUUIDs(req.GetAssignees())
+	if err != nil {
+		return nil, err
+	}
+	input := service.UpsertTodoInput{Title: req.GetTitle(), Description: req.GetDescription(), Assignees: assignees}
+	if req.GetStatus() != todov1.Status_STATUS_UNSPECIFIED {
+		input.Status = mapStatus(req.GetStatus())
+	}
+	todo, created, err := s.svc.Upsert(ctx, id, input)
+	if err != nil {
+		return nil, err
+	}
+	return &todov1.UpsertTodoResponse{Todo: mapTodo(todo), Created: created}, nil
+}
+
 func (s *Server) DeleteTodo(ctx context.Context, req *todov1.DeleteTodoRequest) (*todov1.DeleteTodoResponse, error) {
 	id, err := parseUUID(req.GetId())
 	if err != nil {
@@ -95,12 +168,107 @@ func (s *Server) DeleteTodo(ctx context.Context, req *todov1.DeleteTodoRequest)
 	return &todov1.DeleteTodoResponse{Deleted: deleted}, nil
 }
 
-func ListenAndServe(addr string, svc *service.Service, opts ...grpc.ServerOption) (*grpc.Server, net.Listener, error) {
+// WatchTodos streams changes until the client cancels or falls too far
+// behind; the last resume_token received continues the stream.
+func (s *Server) WatchTodos(req *todov1.WatchTodosRequest, stream grpc.ServerStreamingServer[todov1.TodoEvent]) error {
+	ctx := stream.Context()
+	filter, err := watchFilter(ctx, req)
+	if err != nil {
+		return err
+	}
+	sub, err := s.svc.Watch(ctx, filter, req.GetResumeToken())
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sub.Done():
+			return sub.Err()
+		case e := <-sub.Events():
+			if err := stream.Send(mapEvent(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ImportTodos creates todos as the client streams them in; see
+// service.Import. Items that cannot be parsed are reported like invalid ones.
+func (s *Server) ImportTodos(stream grpc.ClientStreamingServer[todov1.ImportTodosRequest, todov1.ImportTodosResponse]) error {
+	var pending []*todov1.CreateTodoRequest
+	next := func() (service.ImportItem, error) {
+		for len(pending) == 0 {
+			req, err := stream.Recv()
+			if err != nil {
+				return service.ImportItem{}, err
+			}
+			pending = req.GetItems()
+		}
+		item := pending[0]
+		pending = pending[1:]
+		input, err := createInput(item.GetId(), item.GetTitle(), item.GetDescription(), item.GetAssignees())
+		return service.ImportItem{Input: input, Err: err}, nil
+	}
+	result, err := s.svc.Import(stream.Context(), next)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(mapImportResult(result))
+}
+
+// ExportTodos sends every matching todo as it is read from the repository.
+func (s *Server) ExportTodos(req *todov1.ExportTodosRequest, stream grpc.ServerStreamingServer[todov1.Todo]) error {
+	ctx := stream.Context()
+	filter, err := listFilter(ctx, 0, 0, req.GetCreatedBy(), req.GetAssignedTo())
+	if err != nil {
+		return err
+	}
+	return s.svc.Export(ctx, filter, func(todo model.Todo) error {
+		return stream.Send(mapTodo(todo))
+	})
+}
+
+func withIdempotencyKey(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	values := md.Get(IdempotencyKeyMetadata)
+	if len(values) == 0 {
+		return ctx
+	}
+	return service.WithIdempotencyKey(ctx, values[0])
+}
+
+// ListenAndServe registers the v1 and v2 services behind the error, tenant,
+// user and authorization interceptors. Interceptors passed in opts run
+// first; a nil authorizer denies every action.
+func ListenAndServe(addr string, svc *service.Service, users *service.UserService, authorizer authz.Authorizer, opts ...grpc.ServerOption) (*grpc.Server, net.Listener, error) {
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
 		return nil, nil, err
 	}
+	if authorizer == nil {
+		authorizer = authz.DenyAll
+	}
+	opts = append(opts, grpc.ChainUnaryInterceptor(
+		unaryErrorInterceptor,
+		unaryTenantInterceptor,
+		unaryUserInterceptor,
+		unaryAuthzInterceptor(authorizer),
+	))
+	opts = append(opts, grpc.ChainStreamInterceptor(
+		streamErrorInterceptor,
+		streamTenantInterceptor,
+		streamUserInterceptor,
+		streamAuthzInterceptor(authorizer),
+	))
 	server := grpc.NewServer(opts...)
 	todov1.RegisterTodoServiceServer(server, New(svc))
+	todov1.RegisterUserServiceServer(server, NewUserServer(users))
+	todov2.RegisterTodoServiceServer(server, NewV2(svc))
 	return server, lis, nil
 }
@@ -0,0 +1,78 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/authz"
+	"github.com/fuzail-ahmed/codex-test/internal/repository/memory"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+	todov1 "github.com/fuzail-ahmed/codex-test/shared/gen/todo/v1"
+)
+
+func TestUpdateTodoHonorsUpdateMask(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), "acme")
+	srv := New(service.New(memory.New(), 1))
+	created, err := srv.CreateTodo(ctx, &todov1.CreateTodoRequest{Title: "write docs", Description: "draft first"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	id := created.GetTodo().GetId()
+
+	resp, err := srv.UpdateTodo(ctx, &todov1.UpdateTodoRequest{
+		Id:         id,
+		Title:      "ignored",
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"description"}},
+	})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if got := resp.GetTodo(); got.GetDescription() != "" || got.GetTitle() != "write docs" {
+		t.Fatalf("expected only the description to be cleared, got %+v", got)
+	}
+
+	_, err = srv.UpdateTodo(ctx, &todov1.UpdateTodoRequest{
+		Id:         id,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"created_by"}},
+	})
+	if code := status.Code(toStatusError(err)); code != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for unknown path, got %s", code)
+	}
+}
+
+func TestHeaderOnlyCallerCannotDelete(t *testing.T) {
+	policy, err := authz.LoadPolicy("../../../configs/authz-policy.json")
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	srv, lis, err := ListenAndServe("127.0.0.1:0", service.New(memory.New(), 1), nil, policy)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		tenant.MetadataKey, "acme",
+		auth.UserMetadataKey, uuid.NewString(),
+	)
+	_, err = todov1.NewTodoServiceClient(conn).DeleteTodo(ctx, &todov1.DeleteTodoRequest{Id: uuid.NewString()})
+	if code := status.Code(err); code != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for a caller named only by x-user-id, got %v", err)
+	}
+}
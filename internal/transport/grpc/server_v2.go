@@ -0,0 +1,129 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+	todov2 "github.com/fuzail-ahmed/codex-test/shared/gen/todo/v2"
+)
+
+// ServerV2 serves the todo.v2 API from the same service as Server, so both
+// versions always see the same data.
+type ServerV2 struct {
+	todov2.UnimplementedTodoServiceServer
+	svc *service.Service
+}
+
+func NewV2(svc *service.Service) *ServerV2 {
+	return &ServerV2{svc: svc}
+}
+
+func (s *ServerV2) CreateTodo(ctx context.Context, req *todov2.CreateTodoRequest) (*todov2.Todo, error) {
+	todo := req.GetTodo()
+	input, err := createInput(req.GetTodoId(), todo.GetTitle(), todo.GetDescription(), todo.GetAssignees())
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.svc.Create(withIdempotencyKey(ctx), input)
+	if err != nil {
+		return nil, err
+	}
+	return mapTodoV2(created), nil
+}
+
+func (s *ServerV2) BatchCreateTodos(ctx context.Context, req *todov2.BatchCreateTodosRequest) (*todov2.BatchCreateTodosResponse, error) {
+	inputs := make([]service.CreateTodoInput, 0, len(req.GetRequests()))
+	for _, item := range req.GetRequests() {
+		todo := item.GetTodo()
+		input, err := createInput(item.GetTodoId(), todo.GetTitle(), todo.GetDescription(), todo.GetAssignees())
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, input)
+	}
+	todos, err := s.svc.BulkCreate(withIdempotencyKey(ctx), inputs)
+	if err != nil {
+		return nil, err
+	}
+	return &todov2.BatchCreateTodosResponse{Todos: mapTodosV2(todos)}, nil
+}
+
+func (s *ServerV2) GetTodo(ctx context.Context, req *todov2.GetTodoRequest) (*todov2.Todo, error) {
+	id, err := parseUUID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	todo, err := s.svc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapTodoV2(todo), nil
+}
+
+// ListTodos pages with opaque keyset tokens, so todos inserted or deleted
+// between calls neither shift nor repeat results. A full page always
+// carries a token, so the last page may come back empty.
+func (s *ServerV2) ListTodos(ctx context.Context, req *todov2.ListTodosRequest) (*todov2.ListTodosResponse, error) {
+	size := pageSize(req.GetPageSize())
+	filter, err := listFilter(ctx, size, 0, req.GetCreatedBy(), req.GetAssignedTo())
+	if err != nil {
+		return nil, err
+	}
+	filter.After, err = decodePageToken(req.GetPageToken(), filter)
+	if err != nil {
+		return nil, err
+	}
+	todos, err := s.svc.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	resp := &todov2.ListTodosResponse{Todos: mapTodosV2(todos)}
+	if len(todos) == size {
+		resp.NextPageToken = encodePageToken(todos[len(todos)-1], filter)
+	}
+	return resp, nil
+}
+
+func (s *ServerV2) UpdateTodo(ctx context.Context, req *todov2.UpdateTodoRequest) (*todov2.Todo, error) {
+	todo := req.GetTodo()
+	id, err := parseUUID(todo.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if len(req.GetUpdateMask().GetPaths()) == 0 {
+		return nil, maskViolation("update_mask", "must name at least one field")
+	}
+	fields := todoFields{
+		title:       todo.GetTitle(),
+		description: todo.GetDescription(),
+		status:      mapStatusV2(todo.GetStatus()),
+		assignees:   todo.GetAssignees(),
+	}
+	input, err := fields.updateInput(req.GetUpdateMask().GetPaths())
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.svc.Update(ctx, id, input)
+	if err != nil {
+		return nil, err
+	}
+	return mapTodoV2(updated), nil
+}
+
+// DeleteTodo reports NOT_FOUND for a missing todo instead of v1's deleted
+// flag.
+func (s *ServerV2) DeleteTodo(ctx context.Context, req *todov2.DeleteTodoRequest) (*todov2.DeleteTodoResponse, error) {
+	id, err := parseUUID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := s.svc.Delete(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, repository.ErrNotFound
+	}
+	return &todov2.DeleteTodoResponse{}, nil
+}
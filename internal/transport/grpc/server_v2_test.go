@@ -0,0 +1,118 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/fuzail-ahmed/codex-test/internal/repository/memory"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+	todov1 "github.com/fuzail-ahmed/codex-test/shared/gen/todo/v1"
+	todov2 "github.com/fuzail-ahmed/codex-test/shared/gen/todo/v2"
+)
+
+func newServers() (context.Context, *Server, *ServerV2) {
+	svc := service.New(memory.New(), 1)
+	return tenant.WithID(context.Background(), "acme"), New(svc), NewV2(svc)
+}
+
+func TestV1AndV2Consistent(t *testing.T) {
+	ctx, v1, v2 := newServers()
+
+	created, err := v1.CreateTodo(ctx, &todov1.CreateTodoRequest{Title: "from v1", Description: "shared"})
+	if err != nil {
+		t.Fatalf("v1 create: %v", err)
+	}
+	got, err := v2.GetTodo(ctx, &todov2.GetTodoRequest{Id: created.GetTodo().GetId()})
+	if err != nil {
+		t.Fatalf("v2 get: %v", err)
+	}
+	assertSameTodo(t, created.GetTodo(), got)
+
+	updated, err := v2.UpdateTodo(ctx, &todov2.UpdateTodoRequest{
+		Todo:       &todov2.Todo{Id: got.GetId(), Status: todov2.Status_STATUS_DONE},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status", "description"}},
+	})
+	if err != nil {
+		t.Fatalf("v2 update: %v", err)
+	}
+	fetched, err := v1.GetTodo(ctx, &todov1.GetTodoRequest{Id: updated.GetId()})
+	if err != nil {
+		t.Fatalf("v1 get: %v", err)
+	}
+	assertSameTodo(t, fetched.GetTodo(), updated)
+	if fetched.GetTodo().GetDescription() != "" || fetched.GetTodo().GetStatus() != todov1.Status_STATUS_DONE {
+		t.Fatalf("expected masked update to be visible through v1, got %+v", fetched.GetTodo())
+	}
+}
+
+func TestV2ListTodosPaginates(t *testing.T) {
+	ctx, _, v2 := newServers()
+	for _, title := range []string{"one", "two", "three"} {
+		if _, err := v2.CreateTodo(ctx, &todov2.CreateTodoRequest{Todo: &todov2.Todo{Title: title}}); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	req := &todov2.ListTodosRequest{PageSize: 2}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("pagination did not terminate")
+		}
+		resp, err := v2.ListTodos(ctx, req)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		for _, todo := range resp.GetTodos() {
+			if seen[todo.GetId()] {
+				t.Fatalf("todo %s returned twice", todo.GetId())
+			}
+			seen[todo.GetId()] = true
+		}
+		if resp.GetNextPageToken() == "" {
+			break
+		}
+		req.PageToken = resp.GetNextPageToken()
+		if pages == 0 {
+			// A newer todo must not shift the following pages.
+			if _, err := v2.CreateTodo(ctx, &todov2.CreateTodoRequest{Todo: &todov2.Todo{Title: "four"}}); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+		}
+	}
+	if len(seen) != 3 {
+		t.Fatalf("expected 3 todos across pages, got %d", len(seen))
+	}
+
+	first, err := v2.ListTodos(ctx, &todov2.ListTodosRequest{PageSize: 1})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	_, err = v2.ListTodos(ctx, &todov2.ListTodosRequest{PageSize: 1, PageToken: first.GetNextPageToken(), CreatedBy: uuid.NewString()})
+	if code := status.Code(toStatusError(err)); code != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for a token from other filters, got %s", code)
+	}
+
+	_, err = v2.ListTodos(ctx, &todov2.ListTodosRequest{PageToken: "not-a-token"})
+	if code := status.Code(toStatusError(err)); code != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for a bad token, got %s", code)
+	}
+}
+
+func assertSameTodo(t *testing.T, v1 *todov1.Todo, v2 *todov2.Todo) {
+	t.Helper()
+	if v1.GetId() != v2.GetId() || v1.GetTenantId() != v2.GetTenantId() ||
+		v1.GetTitle() != v2.GetTitle() || v1.GetDescription() != v2.GetDescription() ||
+		int32(v1.GetStatus()) != int32(v2.GetStatus()) || v1.GetCreatedBy() != v2.GetCreatedBy() {
+		t.Fatalf("v1 and v2 disagree:\nv1: %+v\nv2: %+v", v1, v2)
+	}
+	if v1.GetCreatedAtUnix() != v2.GetCreateTime().GetSeconds() || v1.GetUpdatedAtUnix() != v2.GetUpdateTime().GetSeconds() {
+		t.Fatalf("timestamps disagree: v1 %d/%d, v2 %v/%v", v1.GetCreatedAtUnix(), v1.GetUpdatedAtUnix(), v2.GetCreateTime(), v2.GetUpdateTime())
+	}
+}
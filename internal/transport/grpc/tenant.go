@@ -0,0 +1,92 @@
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+// UnaryIgnoreUnverifiedTenantInterceptor drops the x-tenant-id metadata
+// from calls that no authenticator identified, like the HTTP
+// IgnoreUnverifiedTenant. Pass it through opts after the authenticating
+// interceptors whenever JWT or client certificate authentication is
+// configured.
+func UnaryIgnoreUnverifiedTenantInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(ignoreUnverifiedTenant(ctx), req)
+	}
+}
+
+// StreamIgnoreUnverifiedTenantInterceptor is the streaming counterpart of
+// UnaryIgnoreUnverifiedTenantInterceptor.
+func StreamIgnoreUnverifiedTenantInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ignoreUnverifiedTenant(ss.Context())})
+	}
+}
+
+func ignoreUnverifiedTenant(ctx context.Context) context.Context {
+	if _, ok := auth.PrincipalFromContext(ctx); ok {
+		return ctx
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	md = md.Copy()
+	md.Delete(tenant.MetadataKey)
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+// unaryTenantInterceptor scopes the call to the tenant of the verified
+// caller, resolved during authentication, and rejects verified callers
+// whose credentials carry none. Calls no authenticator identified fall back
+// to the x-tenant-id metadata. Health checks need no tenant.
+func unaryTenantInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if isHealthCheck(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	ctx, err := withTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamTenantInterceptor is the streaming counterpart of
+// unaryTenantInterceptor.
+func streamTenantInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if isHealthCheck(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	ctx, err := withTenant(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+func withTenant(ctx context.Context) (context.Context, error) {
+	if _, err := tenant.FromContext(ctx); err == nil {
+		return ctx, nil
+	}
+	if _, ok := auth.PrincipalFromContext(ctx); ok {
+		return nil, status.Error(codes.PermissionDenied, "credentials carry no tenant")
+	}
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(tenant.MetadataKey); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	if err := tenant.Validate(id); err != nil {
+		return nil, err
+	}
+	return tenant.WithID(ctx, id), nil
+}
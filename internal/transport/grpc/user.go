@@ -0,0 +1,113 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+	todov1 "github.com/fuzail-ahmed/codex-test/shared/gen/todo/v1"
+)
+
+type UserServer struct {
+	todov1.UnimplementedUserServiceServer
+	svc *service.UserService
+}
+
+func NewUserServer(svc *service.UserService) *UserServer {
+	return &UserServer{svc: svc}
+}
+
+func (s *UserServer) CreateUser(ctx context.Context, req *todov1.CreateUserRequest) (*todov1.CreateUserResponse, error) {
+	user, err := s.svc.Create(ctx, service.CreateUserInput{Email: req.GetEmail(), Name: req.GetName()})
+	if err != nil {
+		return nil, err
+	}
+	return &todov1.CreateUserResponse{User: mapUser(user)}, nil
+}
+
+func (s *UserServer) GetUser(ctx context.Context, req *todov1.GetUserRequest) (*todov1.GetUserResponse, error) {
+	id, err := parseUUID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.svc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &todov1.GetUserResponse{User: mapUser(user)}, nil
+}
+
+func (s *UserServer) ListUsers(ctx context.Context, req *todov1.ListUsersRequest) (*todov1.ListUsersResponse, error) {
+	users, err := s.svc.List(ctx, repository.ListFilter{Limit: int(req.GetLimit()), Offset: int(req.GetOffset())})
+	if err != nil {
+		return nil, err
+	}
+	resp := &todov1.ListUsersResponse{Users: make([]*todov1.User, 0, len(users))}
+	for _, user := range users {
+		resp.Users = append(resp.Users, mapUser(user))
+	}
+	return resp, nil
+}
+
+// unaryUserInterceptor attaches the caller named in the x-user-id metadata,
+// if any, unless an authenticator already identified it. Calls without it
+// proceed anonymously. Like the HTTP header, the metadata is unverified:
+// it carries no groups or roles and is authorized as anonymous.
+func unaryUserInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := withUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamUserInterceptor is the streaming counterpart of
+// unaryUserInterceptor.
+func streamUserInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := withUser(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+func withUser(ctx context.Context) (context.Context, error) {
+	if _, ok := auth.PrincipalFromContext(ctx); ok {
+		return ctx, nil
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+	values := md.Get(auth.UserMetadataKey)
+	if len(values) == 0 {
+		return ctx, nil
+	}
+	id, err := uuid.Parse(values[0])
+	if err != nil || id == uuid.Nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+	return auth.WithPrincipal(ctx, auth.Principal{UserID: id, Unverified: true}), nil
+}
+
+// parseUserFilter resolves a user ID list filter; "me" means the caller.
+func parseUserFilter(ctx context.Context, value string) (uuid.UUID, error) {
+	switch value {
+	case "":
+		return uuid.Nil, nil
+	case "me":
+		principal, ok := auth.PrincipalFromContext(ctx)
+		if !ok {
+			return uuid.Nil, status.Error(codes.InvalidArgument, "\"me\" filter requires "+auth.UserMetadataKey)
+		}
+		return principal.UserID, nil
+	}
+	return parseUUID(value)
+}
@@ -0,0 +1,75 @@
+package httptransport
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/errcode"
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+)
+
+// handleACL serves /todos/{id}/acl and /todos/{id}/acl/{subject_type}/{subject_id}.
+func (h *Handler) handleACL(w http.ResponseWriter, r *http.Request, id uuid.UUID, rest []string) {
+	switch len(rest) {
+	case 0:
+		if r.Method != http.MethodGet {
+			writeError(w, errcode.MethodNotAllowed, "method not allowed")
+			return
+		}
+		grants, err := h.svc.Grants(r.Context(), id)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		items := make([]map[string]any, 0, len(grants))
+		for _, grant := range grants {
+			items = append(items, mapGrant(grant))
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"items": items})
+	case 2:
+		subjectType, subjectID := model.SubjectType(rest[0]), rest[1]
+		switch r.Method {
+		case http.MethodPut:
+			var req struct {
+				Permission string `json:"permission"`
+			}
+			if err := readJSON(r, &req); err != nil {
+				writeError(w, errcode.InvalidArgument, err.Error())
+				return
+			}
+			grant, err := h.svc.Share(r.Context(), id, service.ShareTodoInput{
+				SubjectType: subjectType,
+				SubjectID:   subjectID,
+				Permission:  model.Permission(req.Permission),
+			})
+			if err != nil {
+				writeServiceError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, mapGrant(grant))
+		case http.MethodDelete:
+			deleted, err := h.svc.Unshare(r.Context(), id, subjectType, subjectID)
+			if err != nil {
+				writeServiceError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"deleted": deleted})
+		default:
+			writeError(w, errcode.MethodNotAllowed, "method not allowed")
+		}
+	default:
+		writeError(w, errcode.NotFound, "not found")
+	}
+}
+
+func mapGrant(grant model.Grant) map[string]any {
+	return map[string]any{
+		"todo_id":      grant.TodoID.String(),
+		"subject_type": grant.SubjectType,
+		"subject_id":   grant.SubjectID,
+		"permission":   grant.Permission,
+		"created_at":   grant.CreatedAt,
+	}
+}
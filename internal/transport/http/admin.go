@@ -0,0 +1,69 @@
+package httptransport
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/fuzail-ahmed/codex-test/internal/errcode"
+)
+
+// DBStatsFunc reports connection pool statistics keyed by pool name.
+type DBStatsFunc func() map[string]sql.DBStats
+
+// ReadyFunc reports whether the server should receive traffic.
+type ReadyFunc func(ctx context.Context) bool
+
+// AdminHandler serves operational endpoints. It performs no tenant or
+// authorization checks, so register it only on the admin listener.
+type AdminHandler struct {
+	dbStats DBStatsFunc
+	ready   ReadyFunc
+}
+
+func NewAdminHandler(dbStats DBStatsFunc, ready ReadyFunc) *AdminHandler {
+	return &AdminHandler{dbStats: dbStats, ready: ready}
+}
+
+// Register adds the endpoints. /healthz and /readyz are the liveness and
+// readiness probes; being plaintext, they keep working when the API ports
+// use TLS.
+func (h *AdminHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", h.handleHealth)
+	mux.HandleFunc("/readyz", h.handleReady)
+	mux.HandleFunc("/admin/db/stats", h.handleDBStats)
+}
+
+func (h *AdminHandler) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (h *AdminHandler) handleReady(w http.ResponseWriter, r *http.Request) {
+	if !h.ready(r.Context()) {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "not ready"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+func (h *AdminHandler) handleDBStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, errcode.MethodNotAllowed, "method not allowed")
+		return
+	}
+	pools := make(map[string]any)
+	for name, stats := range h.dbStats() {
+		pools[name] = map[string]any{
+			"max_open":             stats.MaxOpenConnections,
+			"open":                 stats.OpenConnections,
+			"in_use":               stats.InUse,
+			"idle":                 stats.Idle,
+			"wait_count":           stats.WaitCount,
+			"wait_duration_ms":     stats.WaitDuration.Milliseconds(),
+			"max_idle_closed":      stats.MaxIdleClosed,
+			"max_idle_time_closed": stats.MaxIdleTimeClosed,
+			"max_lifetime_closed":  stats.MaxLifetimeClosed,
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"pools": pools})
+}
@@ -0,0 +1,35 @@
+package httptransport
+
+import (
+	"net/http"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/errcode"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+// AuthenticateAPIKey authenticates requests carrying an X-API-Key header.
+// The key's tenant replaces X-Tenant-ID, and a conflicting X-Tenant-ID is
+// rejected. Requests without the header pass through unchanged.
+func AuthenticateAPIKey(keys auth.APIKeyAuthenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := r.Header.Get(auth.APIKeyHeader)
+		if raw == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		r.Header.Del(auth.UserHeader)
+
+		principal, tenantID, err := keys.AuthenticateAPIKey(r.Context(), raw)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		if header := r.Header.Get(tenant.Header); header != "" && header != tenantID {
+			writeError(w, errcode.PermissionDenied, "api key belongs to another tenant")
+			return
+		}
+		ctx := tenant.WithID(auth.WithPrincipal(r.Context(), principal), tenantID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,53 @@
+package httptransport
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/errcode"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+// Authenticate verifies Authorization: Bearer tokens and puts the resulting
+// principal and its tenant into the request context. Requests without a
+// token continue anonymously; invalid tokens are rejected with 401, and an
+// X-Tenant-ID header naming another tenant with 403. The X-User-ID header
+// is dropped so it cannot be used to impersonate another user.
+// Requests already authenticated by an API key are left alone.
+func Authenticate(verifier auth.Verifier, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := auth.PrincipalFromContext(r.Context()); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		r.Header.Del(auth.UserHeader)
+
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			writeUnauthorized(w, "authorization header must be a bearer token")
+			return
+		}
+		principal, tenantID, err := verifier.Verify(strings.TrimSpace(token))
+		if err != nil {
+			writeUnauthorized(w, err.Error())
+			return
+		}
+		if header := r.Header.Get(tenant.Header); header != "" && header != tenantID {
+			writeError(w, errcode.PermissionDenied, "token belongs to another tenant")
+			return
+		}
+		ctx := tenant.WithID(auth.WithPrincipal(r.Context(), principal), tenantID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func writeUnauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	writeError(w, errcode.Unauthenticated, message)
+}
@@ -0,0 +1,39 @@
+package httptransport
+
+import (
+	"net/http"
+
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/errcode"
+	"github.com/fuzail-ahmed/codex-test/internal/tenant"
+)
+
+// AuthenticateClientCert uses a verified TLS client certificate as the
+// caller's identity when no API key or bearer token already provided one,
+// and the certificate's organization as its tenant. An X-Tenant-ID header
+// naming another tenant is rejected with 403. The X-User-ID header is
+// dropped whether or not the caller sent a certificate, so callers without
+// one cannot name themselves either.
+func AuthenticateClientCert(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := auth.PrincipalFromContext(r.Context()); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		r.Header.Del(auth.UserHeader)
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		principal, tenantID := auth.PrincipalFromCertificate(r.TLS.VerifiedChains[0][0])
+		ctx := auth.WithPrincipal(r.Context(), principal)
+		if tenantID != "" {
+			if header := r.Header.Get(tenant.Header); header != "" && header != tenantID {
+				writeError(w, errcode.PermissionDenied, "certificate belongs to another tenant")
+				return
+			}
+			ctx = tenant.WithID(ctx, tenantID)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
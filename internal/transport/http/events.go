@@ -0,0 +1,111 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fuzail-ahmed/codex-test/internal/errcode"
+	"github.com/fuzail-ahmed/codex-test/internal/events"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+)
+
+// keepaliveInterval is how often an idle event stream sends a comment so
+// proxies do not close it.
+const keepaliveInterval = 15 * time.Second
+
+// handleEvents streams todo changes as server-sent events. Each event's id
+// is its resume token, so browsers resume through Last-Event-ID on their
+// own. When the subscription ends the stream sends an "error" event with a
+// problem body and closes.
+func (h *Handler) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, errcode.MethodNotAllowed, "method not allowed")
+		return
+	}
+	filter, err := parseWatchFilter(r)
+	if err != nil {
+		writeError(w, errcode.InvalidArgument, err.Error())
+		return
+	}
+	resumeToken := r.URL.Query().Get("resume_token")
+	if resumeToken == "" {
+		resumeToken = r.Header.Get("Last-Event-ID")
+	}
+	sub, err := h.svc.Watch(r.Context(), filter, resumeToken)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepalive := time.NewTicker(keepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sub.Done():
+			if err := sub.Err(); err != nil {
+				code, detail := errcode.Of(err)
+				writeEvent(w, "", "error", newProblem(w, code, detail))
+				_ = rc.Flush()
+			}
+			return
+		case e := <-sub.Events():
+			writeEvent(w, e.Token(), string(e.Type), mapEvent(e))
+		case <-keepalive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, id, name string, payload any) {
+	data, _ := json.Marshal(payload)
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+}
+
+func mapEvent(e events.Event) map[string]any {
+	return map[string]any{
+		"type":         e.Type,
+		"todo":         mapTodo(e.Todo),
+		"resume_token": e.Token(),
+		"occurred_at":  e.Time,
+	}
+}
+
+// parseWatchFilter reads the types, created_by and assigned_to query
+// parameters. types is a comma-separated list of event types.
+func parseWatchFilter(r *http.Request) (service.WatchFilter, error) {
+	var filter service.WatchFilter
+	if raw := r.URL.Query().Get("types"); raw != "" {
+		for _, t := range strings.Split(raw, ",") {
+			filter.Types = append(filter.Types, events.Type(strings.TrimSpace(t)))
+		}
+	}
+	var err error
+	if filter.CreatedBy, err = parseUserFilter(r, "created_by"); err != nil {
+		return filter, err
+	}
+	if filter.AssignedTo, err = parseUserFilter(r, "assigned_to"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
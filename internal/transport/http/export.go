@@ -0,0 +1,112 @@
+package httptransport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fuzail-ahmed/codex-test/internal/errcode"
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+)
+
+var exportCSVHeader = []string{"id", "tenant_id", "title", "description", "status", "created_by", "assignees", "created_at", "updated_at"}
+
+// handleExport streams every todo matching the list filters as NDJSON or
+// CSV. Rows are written as the repository reads them. A failure before the
+// first row is reported as a problem; after that the connection is aborted
+// so the client cannot mistake a truncated export for a complete one.
+func (h *Handler) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, errcode.MethodNotAllowed, "method not allowed")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "ndjson"
+	}
+	if format != "ndjson" && format != "csv" {
+		writeError(w, errcode.InvalidArgument, "format must be ndjson or csv")
+		return
+	}
+	createdBy, err := parseUserFilter(r, "created_by")
+	if err != nil {
+		writeError(w, errcode.InvalidArgument, err.Error())
+		return
+	}
+	assignedTo, err := parseUserFilter(r, "assigned_to")
+	if err != nil {
+		writeError(w, errcode.InvalidArgument, err.Error())
+		return
+	}
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	var (
+		started bool
+		cw      *csv.Writer
+		enc     *json.Encoder
+	)
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		cw = csv.NewWriter(w)
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc = json.NewEncoder(w)
+	}
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Disposition", `attachment; filename="todos.`+format+`"`)
+		w.WriteHeader(http.StatusOK)
+		if cw != nil {
+			return cw.Write(exportCSVHeader)
+		}
+		return nil
+	}
+	write := func(todo model.Todo) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if cw != nil {
+			return cw.Write(exportCSVRecord(todo))
+		}
+		return enc.Encode(mapTodo(todo))
+	}
+
+	err = h.svc.Export(r.Context(), repository.ListFilter{CreatedBy: createdBy, AssignedTo: assignedTo}, write)
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil && cw != nil {
+		cw.Flush()
+		err = cw.Error()
+	}
+	switch {
+	case err == nil:
+	case !started:
+		writeServiceError(w, err)
+	default:
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func exportCSVRecord(todo model.Todo) []string {
+	createdBy := ""
+	if id, ok := mapOptionalID(todo.CreatedBy).(string); ok {
+		createdBy = id
+	}
+	return []string{
+		todo.ID.String(),
+		todo.TenantID,
+		todo.Title,
+		todo.Description,
+		string(todo.Status),
+		createdBy,
+		strings.Join(mapIDs(todo.Assignees), " "),
+		todo.CreatedAt.UTC().Format(time.RFC3339Nano),
+		todo.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
@@ -9,26 +9,64 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/fuzail-ahmed/codex-test/internal/auth"
+	"github.com/fuzail-ahmed/codex-test/internal/authz"
+	"github.com/fuzail-ahmed/codex-test/internal/errcode"
 	"github.com/fuzail-ahmed/codex-test/internal/model"
 	"github.com/fuzail-ahmed/codex-test/internal/repository"
 	"github.com/fuzail-ahmed/codex-test/internal/service"
 )
 
+// IdempotencyKeyHeader lets clients safely retry POST /todos and POST /todos/bulk.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
 type Handler struct {
-	svc *service.Service
+	svc        *service.Service
+	authorizer authz.Authorizer
+}
+
+type Option func(*options)
+
+type options struct {
+	authorizer authz.Authorizer
 }
 
-func NewHandler(svc *service.Service) *Handler {
-	return &Handler{svc: svc}
+// WithAuthorizer checks every request against the authorizer before it
+// reaches the service. Without it every action is denied; pass
+// authz.AllowAll to disable authorization.
+func WithAuthorizer(authorizer authz.Authorizer) Option {
+	return func(o *options) {
+		o.authorizer = authorizer
+	}
+}
+
+func buildOptions(opts []Option) options {
+	o := options{authorizer: authz.DenyAll}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func NewHandler(svc *service.Service, opts ...Option) *Handler {
+	o := buildOptions(opts)
+	return &Handler{svc: svc, authorizer: o.authorizer}
 }
 
 func (h *Handler) Register(mux *http.ServeMux) {
-	mux.HandleFunc("/todos", h.handleTodos)
-	mux.HandleFunc("/todos/bulk", h.handleBulkCreate)
-	mux.HandleFunc("/todos/", h.handleTodoByID)
+	mux.Handle("/todos", h.guard(h.handleTodos))
+	mux.Handle("/todos/bulk", h.guard(h.handleBulkCreate))
+	mux.Handle("/todos/events", h.guard(h.handleEvents))
+	mux.Handle("/todos/import", h.guard(h.handleImport))
+	mux.Handle("/todos/export", h.guard(h.handleExport))
+	mux.Handle("/todos/", h.guard(h.handleTodoByID))
 	mux.HandleFunc("/healthz", h.handleHealth)
 }
 
+func (h *Handler) guard(fn http.HandlerFunc) http.Handler {
+	return requireTenant(identifyUser(authorize(h.authorizer, todoAction, fn)))
+}
+
 func (h *Handler) handleHealth(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
@@ -40,20 +78,33 @@ func (h *Handler) handleTodos(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		h.handleList(w, r)
 	default:
-		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		writeError(w, errcode.MethodNotAllowed, "method not allowed")
 	}
 }
 
 func (h *Handler) handleCreate(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
+		ID          string      `json:"id"`
+		Title       string      `json:"title"`
+		Description string      `json:"description"`
+		Assignees   []uuid.UUID `json:"assignees"`
 	}
 	if err := readJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, errcode.InvalidArgument, err.Error())
+		return
+	}
+	id, err := parseOptionalID(req.ID)
+	if err != nil {
+		writeError(w, errcode.InvalidArgument, err.Error())
 		return
 	}
-	result, err := h.svc.Create(r.Context(), service.CreateTodoInput{Title: req.Title, Description: req.Description})
+	ctx := service.WithIdempotencyKey(r.Context(), r.Header.Get(IdempotencyKeyHeader))
+	result, err := h.svc.Create(ctx, service.CreateTodoInput{
+		ID:          id,
+		Title:       req.Title,
+		Description: req.Description,
+		Assignees:   req.Assignees,
+	})
 	if err != nil {
 		writeServiceError(w, err)
 		return
@@ -63,24 +114,51 @@ func (h *Handler) handleCreate(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) handleBulkCreate(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		writeError(w, errcode.MethodNotAllowed, "method not allowed")
+		return
+	}
+	mode := r.URL.Query().Get("mode")
+	if mode != "" && mode != "atomic" && mode != "partial" {
+		writeError(w, errcode.InvalidArgument, "mode must be atomic or partial")
 		return
 	}
 	var req struct {
 		Items []struct {
-			Title       string `json:"title"`
-			Description string `json:"description"`
+			ID          string      `json:"id"`
+			Title       string      `json:"title"`
+			Description string      `json:"description"`
+			Assignees   []uuid.UUID `json:"assignees"`
 		} `json:"items"`
 	}
 	if err := readJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, errcode.InvalidArgument, err.Error())
 		return
 	}
 	inputs := make([]service.CreateTodoInput, 0, len(req.Items))
 	for _, item := range req.Items {
-		inputs = append(inputs, service.CreateTodoInput{Title: item.Title, Description: item.Description})
+		id, err := parseOptionalID(item.ID)
+		if err != nil {
+			writeError(w, errcode.InvalidArgument, err.Error())
+			return
+		}
+		inputs = append(inputs, service.CreateTodoInput{
+			ID:          id,
+			Title:       item.Title,
+			Description: item.Description,
+			Assignees:   item.Assignees,
+		})
 	}
-	result, err := h.svc.BulkCreate(r.Context(), inputs)
+	ctx := service.WithIdempotencyKey(r.Context(), r.Header.Get(IdempotencyKeyHeader))
+	if mode == "partial" {
+		results, err := h.svc.BulkCreatePartial(ctx, inputs)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusMultiStatus, mapBulkResults(results))
+		return
+	}
+	result, err := h.svc.BulkCreate(ctx, inputs)
 	if err != nil {
 		writeServiceError(w, err)
 		return
@@ -91,7 +169,22 @@ func (h *Handler) handleBulkCreate(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) handleList(w http.ResponseWriter, r *http.Request) {
 	limit := parseInt(r.URL.Query().Get("limit"), 50)
 	offset := parseInt(r.URL.Query().Get("offset"), 0)
-	result, err := h.svc.List(r.Context(), repository.ListFilter{Limit: limit, Offset: offset})
+	createdBy, err := parseUserFilter(r, "created_by")
+	if err != nil {
+		writeError(w, errcode.InvalidArgument, err.Error())
+		return
+	}
+	assignedTo, err := parseUserFilter(r, "assigned_to")
+	if err != nil {
+		writeError(w, errcode.InvalidArgument, err.Error())
+		return
+	}
+	result, err := h.svc.List(r.Context(), repository.ListFilter{
+		Limit:      limit,
+		Offset:     offset,
+		CreatedBy:  createdBy,
+		AssignedTo: assignedTo,
+	})
 	if err != nil {
 		writeServiceError(w, err)
 		return
@@ -102,12 +195,21 @@ func (h *Handler) handleList(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) handleTodoByID(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/todos/")
 	if path == "" {
-		writeError(w, http.StatusNotFound, "not found")
+		writeError(w, errcode.NotFound, "not found")
 		return
 	}
-	id, err := uuid.Parse(path)
+	parts := strings.Split(path, "/")
+	id, err := uuid.Parse(parts[0])
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "invalid id")
+		writeError(w, errcode.InvalidArgument, "invalid id")
+		return
+	}
+	if len(parts) > 1 {
+		if parts[1] != "acl" {
+			writeError(w, errcode.NotFound, "not found")
+			return
+		}
+		h.handleACL(w, r, id, parts[2:])
 		return
 	}
 
@@ -121,12 +223,13 @@ func (h *Handler) handleTodoByID(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusOK, mapTodo(result))
 	case http.MethodPatch:
 		var req struct {
-			Title       *string `json:"title"`
-			Description *string `json:"description"`
-			Status      *string `json:"status"`
+			Title       *string      `json:"title"`
+			Description *string      `json:"description"`
+			Status      *string      `json:"status"`
+			Assignees   *[]uuid.UUID `json:"assignees"`
 		}
 		if err := readJSON(r, &req); err != nil {
-			writeError(w, http.StatusBadRequest, err.Error())
+			writeError(w, errcode.InvalidArgument, err.Error())
 			return
 		}
 		var status *model.Status
@@ -138,12 +241,39 @@ func (h *Handler) handleTodoByID(w http.ResponseWriter, r *http.Request) {
 			Title:       req.Title,
 			Description: req.Description,
 			Status:      status,
+			Assignees:   req.Assignees,
 		})
 		if err != nil {
 			writeServiceError(w, err)
 			return
 		}
 		writeJSON(w, http.StatusOK, mapTodo(result))
+	case http.MethodPut:
+		var req struct {
+			Title       string      `json:"title"`
+			Description string      `json:"description"`
+			Status      string      `json:"status"`
+			Assignees   []uuid.UUID `json:"assignees"`
+		}
+		if err := readJSON(r, &req); err != nil {
+			writeError(w, errcode.InvalidArgument, err.Error())
+			return
+		}
+		result, created, err := h.svc.Upsert(r.Context(), id, service.UpsertTodoInput{
+			Title:       req.Title,
+			Description: req.Description,
+			Status:      model.Status(req.Status),
+			Assignees:   req.Assignees,
+		})
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		status := http.StatusOK
+		if created {
+			status = http.StatusCreated
+		}
+		writeJSON(w, status, mapTodo(result))
 	case http.MethodDelete:
 		deleted, err := h.svc.Delete(r.Context(), id)
 		if err != nil {
@@ -152,7 +282,7 @@ func (h *Handler) handleTodoByID(w http.ResponseWriter, r *http.Request) {
 		}
 		writeJSON(w, http.StatusOK, map[string]any{"deleted": deleted})
 	default:
-		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		writeError(w, errcode.MethodNotAllowed, "method not allowed")
 	}
 }
 
@@ -175,33 +305,15 @@ func writeJSON(w http.ResponseWriter, status int, payload any) {
 	_ = json.NewEncoder(w).Encode(payload)
 }
 
-func writeError(w http.ResponseWriter, status int, message string) {
-	writeJSON(w, status, map[string]any{
-		"error": map[string]string{
-			"message": message,
-		},
-	})
-}
-
-func writeServiceError(w http.ResponseWriter, err error) {
-	switch {
-	case errors.Is(err, service.ErrValidation):
-		writeError(w, http.StatusBadRequest, err.Error())
-	case errors.Is(err, repository.ErrNotFound):
-		writeError(w, http.StatusNotFound, "not found")
-	case errors.Is(err, repository.ErrConflict):
-		writeError(w, http.StatusConflict, "conflict")
-	default:
-		writeError(w, http.StatusInternalServerError, "internal error")
-	}
-}
-
 func mapTodo(todo model.Todo) map[string]any {
 	return map[string]any{
 		"id":          todo.ID.String(),
+		"tenant_id":   todo.TenantID,
 		"title":       todo.Title,
 		"description": todo.Description,
 		"status":      todo.Status,
+		"created_by":  mapOptionalID(todo.CreatedBy),
+		"assignees":   mapIDs(todo.Assignees),
 		"created_at":  todo.CreatedAt,
 		"updated_at":  todo.UpdatedAt,
 	}
@@ -215,6 +327,97 @@ func mapTodos(todos []model.Todo) map[string]any {
 	return map[string]any{"items": items}
 }
 
+func mapBulkResults(results []service.BulkItemResult) map[string]any {
+	items := make([]map[string]any, 0, len(results))
+	failed := 0
+	for _, result := range results {
+		item := map[string]any{"index": result.Index}
+		if result.Err != nil {
+			failed++
+			item["error"] = mapItemError(result.Err)
+		} else {
+			item["todo"] = mapTodo(result.Todo)
+		}
+		items = append(items, item)
+	}
+	return map[string]any{
+		"results": items,
+		"created": len(results) - failed,
+		"failed":  failed,
+	}
+}
+
+// mapItemError reports the failure of one item of a bulk request or import
+// as a problem, the same body the request would have failed with on its
+// own, minus the request ID.
+func mapItemError(err error) problem {
+	code, detail := errcode.Of(err)
+	var malformed *malformedItemError
+	if errors.As(err, &malformed) {
+		code, detail = errcode.InvalidArgument, err.Error()
+	}
+	entry := errcode.Lookup(code)
+	p := problem{
+		Type:   errcode.TypeURI(code),
+		Title:  entry.Title,
+		Status: entry.HTTPStatus,
+		Detail: detail,
+		Code:   code,
+	}
+	var validation *service.ValidationError
+	if errors.As(err, &validation) {
+		p.InvalidParams = invalidParams(validation)
+	}
+	return p
+}
+
+func parseOptionalID(val string) (uuid.UUID, error) {
+	if val == "" {
+		return uuid.Nil, nil
+	}
+	id, err := uuid.Parse(val)
+	if err != nil {
+		return uuid.Nil, errors.New("invalid id")
+	}
+	return id, nil
+}
+
+// parseUserFilter reads a user ID query parameter. The value "me" resolves
+// to the calling user and requires one.
+func parseUserFilter(r *http.Request, name string) (uuid.UUID, error) {
+	val := r.URL.Query().Get(name)
+	switch val {
+	case "":
+		return uuid.Nil, nil
+	case "me":
+		principal, ok := auth.PrincipalFromContext(r.Context())
+		if !ok {
+			return uuid.Nil, errors.New(name + "=me requires " + auth.UserHeader)
+		}
+		return principal.UserID, nil
+	}
+	id, err := uuid.Parse(val)
+	if err != nil {
+		return uuid.Nil, errors.New("invalid " + name)
+	}
+	return id, nil
+}
+
+func mapOptionalID(id uuid.UUID) any {
+	if id == uuid.Nil {
+		return nil
+	}
+	return id.String()
+}
+
+func mapIDs(ids []uuid.UUID) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, id.String())
+	}
+	return out
+}
+
 func parseInt(val string, def int) int {
 	if val == "" {
 		return def
@@ -0,0 +1,111 @@
+package httptransport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/errcode"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+)
+
+// maxImportLine is the longest NDJSON line POST /todos/import accepts.
+const maxImportLine = 1 << 20
+
+// malformedItemError is an import line that is not a valid item.
+type malformedItemError struct {
+	err error
+}
+
+func (e *malformedItemError) Error() string {
+	return e.err.Error()
+}
+
+// handleImport creates todos from a newline-delimited JSON body with one
+// create request per line; empty lines are skipped and not counted. The
+// body is read as it arrives, so it is not bound by the server's read
+// timeout or by memory.
+func (h *Handler) handleImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, errcode.MethodNotAllowed, "method not allowed")
+		return
+	}
+	defer r.Body.Close()
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLine)
+	next := func() (service.ImportItem, error) {
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			return decodeImportLine(line), nil
+		}
+		if err := scanner.Err(); err != nil {
+			if errors.Is(err, bufio.ErrTooLong) {
+				return service.ImportItem{}, fmt.Errorf("%w: line longer than %d bytes", service.ErrValidation, maxImportLine)
+			}
+			return service.ImportItem{}, err
+		}
+		return service.ImportItem{}, io.EOF
+	}
+	result, err := h.svc.Import(r.Context(), next)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, mapImportResult(result))
+}
+
+func decodeImportLine(line []byte) service.ImportItem {
+	var req struct {
+		ID          string      `json:"id"`
+		Title       string      `json:"title"`
+		Description string      `json:"description"`
+		Assignees   []uuid.UUID `json:"assignees"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return service.ImportItem{Err: &malformedItemError{err: err}}
+	}
+	id, err := parseOptionalID(req.ID)
+	if err != nil {
+		return service.ImportItem{Err: &malformedItemError{err: err}}
+	}
+	return service.ImportItem{Input: service.CreateTodoInput{
+		ID:          id,
+		Title:       req.Title,
+		Description: req.Description,
+		Assignees:   req.Assignees,
+	}}
+}
+
+// importLineError is a problem for one rejected line of an import.
+type importLineError struct {
+	Line int `json:"line"`
+	problem
+}
+
+func mapImportResult(result service.ImportResult) map[string]any {
+	errs := make([]importLineError, 0, len(result.Errors))
+	for _, lineErr := range result.Errors {
+		errs = append(errs, importLineError{Line: lineErr.Line, problem: mapItemError(lineErr.Err)})
+	}
+	return map[string]any{
+		"created": result.Created,
+		"failed":  result.Failed,
+		"errors":  errs,
+	}
+}
@@ -0,0 +1,88 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/fuzail-ahmed/codex-test/internal/errcode"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+)
+
+const problemContentType = "application/problem+json"
+
+// problem is an RFC 7807 problem details body. Code is the errcode catalog
+// entry clients should switch on; Instance is the request ID.
+type problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	Code          errcode.Code   `json:"code"`
+	InvalidParams []invalidParam `json:"invalid_params,omitempty"`
+}
+
+// invalidParam is one field violation of a validation problem.
+type invalidParam struct {
+	Name   string `json:"name"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+func newProblem(w http.ResponseWriter, code errcode.Code, detail string) problem {
+	entry := errcode.Lookup(code)
+	return problem{
+		Type:     errcode.TypeURI(code),
+		Title:    entry.Title,
+		Status:   entry.HTTPStatus,
+		Detail:   detail,
+		Instance: w.Header().Get(RequestIDHeader),
+		Code:     code,
+	}
+}
+
+func writeProblem(w http.ResponseWriter, p problem) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// writeError reports a transport-level failure, such as a malformed body or
+// an unsupported method, that did not come from the service.
+func writeError(w http.ResponseWriter, code errcode.Code, detail string) {
+	writeProblem(w, newProblem(w, code, detail))
+}
+
+// writeServiceError reports err as a problem. Errors that fall back to
+// Internal are logged first, since the response does not carry them.
+func writeServiceError(w http.ResponseWriter, err error) {
+	code, detail := errcode.Of(err)
+	p := newProblem(w, code, detail)
+	if code == errcode.Internal {
+		log.Printf("request %s: internal error: %v", p.Instance, err)
+	}
+	var validation *service.ValidationError
+	if errors.As(err, &validation) {
+		p.InvalidParams = invalidParams(validation)
+	}
+	var unavailable *repository.UnavailableError
+	if errors.As(err, &unavailable) {
+		seconds := int(math.Ceil(unavailable.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+	}
+	writeProblem(w, p)
+}
+
+func invalidParams(err *service.ValidationError) []invalidParam {
+	params := make([]invalidParam, len(err.Violations))
+	for i, v := range err.Violations {
+		params[i] = invalidParam{Name: v.Field, Code: v.Code, Reason: v.Message, Limit: v.Limit}
+	}
+	return params
+}
@@ -0,0 +1,97 @@
+package httptransport
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/fuzail-ahmed/codex-test/internal/authz"
+	"github.com/fuzail-ahmed/codex-test/internal/errcode"
+	"github.com/fuzail-ahmed/codex-test/internal/model"
+	"github.com/fuzail-ahmed/codex-test/internal/repository"
+	"github.com/fuzail-ahmed/codex-test/internal/service"
+)
+
+type UserHandler struct {
+	svc        *service.UserService
+	authorizer authz.Authorizer
+}
+
+func NewUserHandler(svc *service.UserService, opts ...Option) *UserHandler {
+	o := buildOptions(opts)
+	return &UserHandler{svc: svc, authorizer: o.authorizer}
+}
+
+func (h *UserHandler) Register(mux *http.ServeMux) {
+	mux.Handle("/users", requireTenant(identifyUser(authorize(h.authorizer, userAction, http.HandlerFunc(h.handleUsers)))))
+	mux.Handle("/users/", requireTenant(identifyUser(authorize(h.authorizer, userAction, http.HandlerFunc(h.handleUserByID)))))
+}
+
+func (h *UserHandler) handleUsers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var req struct {
+			Email string `json:"email"`
+			Name  string `json:"name"`
+		}
+		if err := readJSON(r, &req); err != nil {
+			writeError(w, errcode.InvalidArgument, err.Error())
+			return
+		}
+		result, err := h.svc.Create(r.Context(), service.CreateUserInput{Email: req.Email, Name: req.Name})
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, mapUser(result))
+	case http.MethodGet:
+		limit := parseInt(r.URL.Query().Get("limit"), 50)
+		offset := parseInt(r.URL.Query().Get("offset"), 0)
+		result, err := h.svc.List(r.Context(), repository.ListFilter{Limit: limit, Offset: offset})
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		items := make([]map[string]any, 0, len(result))
+		for _, user := range result {
+			items = append(items, mapUser(user))
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"items": items})
+	default:
+		writeError(w, errcode.MethodNotAllowed, "method not allowed")
+	}
+}
+
+func (h *UserHandler) handleUserByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/users/")
+	if path == "" {
+		writeError(w, errcode.NotFound, "not found")
+		return
+	}
+	id, err := uuid.Parse(path)
+	if err != nil {
+		writeError(w, errcode.InvalidArgument, "invalid id")
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, errcode.MethodNotAllowed, "method not allowed")
+		return
+	}
+	result, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, mapUser(result))
+}
+
+func mapUser(user model.User) map[string]any {
+	return map[string]any{
+		"id":         user.ID.String(),
+		"tenant_id":  user.TenantID,
+		"email":      user.Email,
+		"name":       user.Name,
+		"created_at": user.CreatedAt,
+	}
+}
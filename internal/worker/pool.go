@@ -13,6 +13,7 @@ type Pool[I any, O any] struct {
 	Work    WorkFunc[I, O]
 }
 
+// Run processes every input and stops at the first error, returning it.
 func (p Pool[I, O]) Run(ctx context.Context, inputs []I) ([]O, error) {
 	if p.Workers < 1 {
 		p.Workers = 1
@@ -82,4 +83,50 @@ func (p Pool[I, O]) Run(ctx context.Context, inputs []I) ([]O, error) {
 		return nil, err
 	}
 	return outs, nil
-}
\ No newline at end of file
+}
+
+// RunAll processes every input without stopping on item errors. The returned
+// slices are index-aligned with inputs; the final error is only set when the
+// context is cancelled before all inputs were processed.
+func (p Pool[I, O]) RunAll(ctx context.Context, inputs []I) ([]O, []error, error) {
+	if p.Workers < 1 {
+		p.Workers = 1
+	}
+
+	type job struct {
+		idx int
+		in  I
+	}
+
+	jobs := make(chan job)
+	outs := make([]O, len(inputs))
+	errs := make([]error, len(inputs))
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				outs[j.idx], errs[j.idx] = p.Work(ctx, j.in)
+			}
+		}()
+	}
+
+	for i, in := range inputs {
+		select {
+		case jobs <- job{idx: i, in: in}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	return outs, errs, nil
+}
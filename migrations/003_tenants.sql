@@ -0,0 +1,15 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE todos ALTER COLUMN tenant_id DROP DEFAULT;
+
+CREATE INDEX IF NOT EXISTS idx_todos_tenant_created_at ON todos (tenant_id, created_at DESC);
+
+-- The application sets app.tenant_id per transaction; FORCE applies the
+-- policy to the table owner as well. Superusers still bypass RLS, so queries
+-- also filter on tenant_id explicitly.
+ALTER TABLE todos ENABLE ROW LEVEL SECURITY;
+ALTER TABLE todos FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS todos_tenant_isolation ON todos;
+CREATE POLICY todos_tenant_isolation ON todos
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
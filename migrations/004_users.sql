@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS users (
+    id UUID PRIMARY KEY,
+    tenant_id TEXT NOT NULL,
+    email TEXT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (tenant_id, email)
+);
+
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS users_tenant_isolation ON users;
+CREATE POLICY users_tenant_isolation ON users
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+-- created_by is not a foreign key: callers may be authenticated by an
+-- external identity provider without a row in users.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS created_by UUID;
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS assignees UUID[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_todos_tenant_created_by ON todos (tenant_id, created_by);
+CREATE INDEX IF NOT EXISTS idx_todos_assignees ON todos USING GIN (assignees);
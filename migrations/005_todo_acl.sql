@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS todo_acl (
+    todo_id UUID NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+    tenant_id TEXT NOT NULL,
+    subject_type TEXT NOT NULL CHECK (subject_type IN ('user', 'group')),
+    subject_id TEXT NOT NULL,
+    permission TEXT NOT NULL CHECK (permission IN ('viewer', 'editor', 'owner')),
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (todo_id, subject_type, subject_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_acl_subject ON todo_acl (tenant_id, subject_type, subject_id);
+
+ALTER TABLE todo_acl ENABLE ROW LEVEL SECURITY;
+ALTER TABLE todo_acl FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS todo_acl_tenant_isolation ON todo_acl;
+CREATE POLICY todo_acl_tenant_isolation ON todo_acl
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
@@ -0,0 +1,41 @@
+-- tenant_todo_counts keeps the number of todos per tenant. Inserts lock the
+-- tenant's row until they commit, so quota checks made after an insert see
+-- every concurrent insert of the same tenant. Like api_keys it is not under
+-- row-level security; only the triggers below write to it.
+CREATE TABLE IF NOT EXISTS tenant_todo_counts (
+    tenant_id TEXT PRIMARY KEY,
+    todo_count BIGINT NOT NULL DEFAULT 0 CHECK (todo_count >= 0)
+);
+
+CREATE OR REPLACE FUNCTION count_tenant_todos() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO tenant_todo_counts AS c (tenant_id, todo_count)
+        SELECT tenant_id, count(*) FROM new_rows GROUP BY tenant_id
+        ON CONFLICT (tenant_id) DO UPDATE SET todo_count = c.todo_count + EXCLUDED.todo_count;
+    ELSE
+        UPDATE tenant_todo_counts c SET todo_count = c.todo_count - d.n
+        FROM (SELECT tenant_id, count(*) AS n FROM old_rows GROUP BY tenant_id) d
+        WHERE c.tenant_id = d.tenant_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS todos_count_insert ON todos;
+CREATE TRIGGER todos_count_insert AFTER INSERT ON todos
+    REFERENCING NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION count_tenant_todos();
+
+DROP TRIGGER IF EXISTS todos_count_delete ON todos;
+CREATE TRIGGER todos_count_delete AFTER DELETE ON todos
+    REFERENCING OLD TABLE AS old_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION count_tenant_todos();
+
+-- Backfill with row-level security lifted for the owner; the ALTERs lock
+-- todos, so no insert slips in between the count and the triggers.
+ALTER TABLE todos NO FORCE ROW LEVEL SECURITY;
+INSERT INTO tenant_todo_counts (tenant_id, todo_count)
+SELECT tenant_id, count(*) FROM todos GROUP BY tenant_id
+ON CONFLICT (tenant_id) DO UPDATE SET todo_count = EXCLUDED.todo_count;
+ALTER TABLE todos FORCE ROW LEVEL SECURITY;
@@ -0,0 +1,2 @@
+-- A NULL response marks a key reserved by a request that is still running.
+ALTER TABLE idempotency_keys ALTER COLUMN response DROP NOT NULL;
@@ -0,0 +1,11 @@
+-- todo_events is the change log every API instance appends to and polls, so
+-- watchers see changes made through any instance. It is not under row-level
+-- security: each instance reads every tenant's events and filters them per
+-- watcher.
+CREATE TABLE IF NOT EXISTS todo_events (
+    seq BIGSERIAL PRIMARY KEY,
+    tenant_id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL
+);
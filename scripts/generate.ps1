@@ -4,7 +4,14 @@ param(
 
 $protoFiles = Get-ChildItem -Path $ProtoDir -Filter "*.proto" | ForEach-Object { $_.FullName }
 
-protoc --proto_path=$ProtoDir `
+# google/rpc/status.proto is vendored under third_party/googleapis.
+protoc --proto_path=$ProtoDir --proto_path=third_party/googleapis `
   --go_out=shared/gen/todo/v1 --go-grpc_out=shared/gen/todo/v1 `
   --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative `
-  $protoFiles
\ No newline at end of file
+  $protoFiles
+
+# v2 is compiled from the proto root so its registered path is unique.
+protoc --proto_path=proto `
+  --go_out=shared/gen --go-grpc_out=shared/gen `
+  --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative `
+  todo/v2/todo.proto
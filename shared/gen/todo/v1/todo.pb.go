@@ -7,8 +7,10 @@
 package todo_v1
 
 import (
+	status "google.golang.org/genproto/googleapis/rpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -70,6 +72,159 @@ func (Status) EnumDescriptor() ([]byte, []int) {
 	return file_todo_proto_rawDescGZIP(), []int{0}
 }
 
+type Permission int32
+
+const (
+	Permission_PERMISSION_UNSPECIFIED Permission = 0
+	Permission_PERMISSION_VIEWER      Permission = 1
+	Permission_PERMISSION_EDITOR      Permission = 2
+	Permission_PERMISSION_OWNER       Permission = 3
+)
+
+// Enum value maps for Permission.
+var (
+	Permission_name = map[int32]string{
+		0: "PERMISSION_UNSPECIFIED",
+		1: "PERMISSION_VIEWER",
+		2: "PERMISSION_EDITOR",
+		3: "PERMISSION_OWNER",
+	}
+	Permission_value = map[string]int32{
+		"PERMISSION_UNSPECIFIED": 0,
+		"PERMISSION_VIEWER":      1,
+		"PERMISSION_EDITOR":      2,
+		"PERMISSION_OWNER":       3,
+	}
+)
+
+func (x Permission) Enum() *Permission {
+	p := new(Permission)
+	*p = x
+	return p
+}
+
+func (x Permission) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Permission) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[1].Descriptor()
+}
+
+func (Permission) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[1]
+}
+
+func (x Permission) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Permission.Descriptor instead.
+func (Permission) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{1}
+}
+
+type SubjectType int32
+
+const (
+	SubjectType_SUBJECT_TYPE_UNSPECIFIED SubjectType = 0
+	SubjectType_SUBJECT_TYPE_USER        SubjectType = 1
+	SubjectType_SUBJECT_TYPE_GROUP       SubjectType = 2
+)
+
+// Enum value maps for SubjectType.
+var (
+	SubjectType_name = map[int32]string{
+		0: "SUBJECT_TYPE_UNSPECIFIED",
+		1: "SUBJECT_TYPE_USER",
+		2: "SUBJECT_TYPE_GROUP",
+	}
+	SubjectType_value = map[string]int32{
+		"SUBJECT_TYPE_UNSPECIFIED": 0,
+		"SUBJECT_TYPE_USER":        1,
+		"SUBJECT_TYPE_GROUP":       2,
+	}
+)
+
+func (x SubjectType) Enum() *SubjectType {
+	p := new(SubjectType)
+	*p = x
+	return p
+}
+
+func (x SubjectType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SubjectType) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[2].Descriptor()
+}
+
+func (SubjectType) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[2]
+}
+
+func (x SubjectType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SubjectType.Descriptor instead.
+func (SubjectType) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{2}
+}
+
+type TodoEventType int32
+
+const (
+	TodoEventType_TODO_EVENT_TYPE_UNSPECIFIED TodoEventType = 0
+	TodoEventType_TODO_EVENT_TYPE_CREATED     TodoEventType = 1
+	TodoEventType_TODO_EVENT_TYPE_UPDATED     TodoEventType = 2
+	TodoEventType_TODO_EVENT_TYPE_DELETED     TodoEventType = 3
+)
+
+// Enum value maps for TodoEventType.
+var (
+	TodoEventType_name = map[int32]string{
+		0: "TODO_EVENT_TYPE_UNSPECIFIED",
+		1: "TODO_EVENT_TYPE_CREATED",
+		2: "TODO_EVENT_TYPE_UPDATED",
+		3: "TODO_EVENT_TYPE_DELETED",
+	}
+	TodoEventType_value = map[string]int32{
+		"TODO_EVENT_TYPE_UNSPECIFIED": 0,
+		"TODO_EVENT_TYPE_CREATED":     1,
+		"TODO_EVENT_TYPE_UPDATED":     2,
+		"TODO_EVENT_TYPE_DELETED":     3,
+	}
+)
+
+func (x TodoEventType) Enum() *TodoEventType {
+	p := new(TodoEventType)
+	*p = x
+	return p
+}
+
+func (x TodoEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TodoEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[3].Descriptor()
+}
+
+func (TodoEventType) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[3]
+}
+
+func (x TodoEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TodoEventType.Descriptor instead.
+func (TodoEventType) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{3}
+}
+
 type Todo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -78,6 +233,10 @@ type Todo struct {
 	Status        Status                 `protobuf:"varint,4,opt,name=status,proto3,enum=todo.v1.Status" json:"status,omitempty"`
 	CreatedAtUnix int64                  `protobuf:"varint,5,opt,name=created_at_unix,json=createdAtUnix,proto3" json:"created_at_unix,omitempty"`
 	UpdatedAtUnix int64                  `protobuf:"varint,6,opt,name=updated_at_unix,json=updatedAtUnix,proto3" json:"updated_at_unix,omitempty"`
+	TenantId      string                 `protobuf:"bytes,7,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Empty when the todo was created anonymously.
+	CreatedBy     string   `protobuf:"bytes,8,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	Assignees     []string `protobuf:"bytes,9,rep,name=assignees,proto3" json:"assignees,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -154,10 +313,34 @@ func (x *Todo) GetUpdatedAtUnix() int64 {
 	return 0
 }
 
+func (x *Todo) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *Todo) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *Todo) GetAssignees() []string {
+	if x != nil {
+		return x.Assignees
+	}
+	return nil
+}
+
 type CreateTodoRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Title       string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// Optional client-supplied UUID; generated by the server when empty.
+	Id            string   `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Assignees     []string `protobuf:"bytes,4,rep,name=assignees,proto3" json:"assignees,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -206,6 +389,20 @@ func (x *CreateTodoRequest) GetDescription() string {
 	return ""
 }
 
+func (x *CreateTodoRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateTodoRequest) GetAssignees() []string {
+	if x != nil {
+		return x.Assignees
+	}
+	return nil
+}
+
 type CreateTodoResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Todo          *Todo                  `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
@@ -251,10 +448,13 @@ func (x *CreateTodoResponse) GetTodo() *Todo {
 }
 
 type BulkCreateTodosRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Items         []*CreateTodoRequest   `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Items []*CreateTodoRequest   `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// When set, invalid items are reported in results instead of failing the
+	// whole batch, and the valid items are still created.
+	PartialSuccess bool `protobuf:"varint,2,opt,name=partial_success,json=partialSuccess,proto3" json:"partial_success,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *BulkCreateTodosRequest) Reset() {
@@ -294,16 +494,99 @@ func (x *BulkCreateTodosRequest) GetItems() []*CreateTodoRequest {
 	return nil
 }
 
+func (x *BulkCreateTodosRequest) GetPartialSuccess() bool {
+	if x != nil {
+		return x.PartialSuccess
+	}
+	return false
+}
+
+type BulkCreateResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Index int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Todo  *Todo                  `protobuf:"bytes,2,opt,name=todo,proto3" json:"todo,omitempty"`
+	// Deprecated: use status. Still set to status.message for older clients.
+	//
+	// Deprecated: Marked as deprecated in todo.proto.
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// Why the item failed, with google.rpc.BadRequest field violations for
+	// invalid items. Unset when the item was created.
+	Status        *status.Status `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCreateResult) Reset() {
+	*x = BulkCreateResult{}
+	mi := &file_todo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCreateResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCreateResult) ProtoMessage() {}
+
+func (x *BulkCreateResult) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCreateResult.ProtoReflect.Descriptor instead.
+func (*BulkCreateResult) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BulkCreateResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BulkCreateResult) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+// Deprecated: Marked as deprecated in todo.proto.
+func (x *BulkCreateResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BulkCreateResult) GetStatus() *status.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
 type BulkCreateTodosResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Todos         []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Todos []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"`
+	// Populated only in partial_success mode, one entry per request item.
+	Results       []*BulkCreateResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *BulkCreateTodosResponse) Reset() {
 	*x = BulkCreateTodosResponse{}
-	mi := &file_todo_proto_msgTypes[4]
+	mi := &file_todo_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -315,7 +598,7 @@ func (x *BulkCreateTodosResponse) String() string {
 func (*BulkCreateTodosResponse) ProtoMessage() {}
 
 func (x *BulkCreateTodosResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[4]
+	mi := &file_todo_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -328,7 +611,7 @@ func (x *BulkCreateTodosResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BulkCreateTodosResponse.ProtoReflect.Descriptor instead.
 func (*BulkCreateTodosResponse) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{4}
+	return file_todo_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *BulkCreateTodosResponse) GetTodos() []*Todo {
@@ -338,6 +621,13 @@ func (x *BulkCreateTodosResponse) GetTodos() []*Todo {
 	return nil
 }
 
+func (x *BulkCreateTodosResponse) GetResults() []*BulkCreateResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
 type GetTodoRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -347,7 +637,7 @@ type GetTodoRequest struct {
 
 func (x *GetTodoRequest) Reset() {
 	*x = GetTodoRequest{}
-	mi := &file_todo_proto_msgTypes[5]
+	mi := &file_todo_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -359,7 +649,7 @@ func (x *GetTodoRequest) String() string {
 func (*GetTodoRequest) ProtoMessage() {}
 
 func (x *GetTodoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[5]
+	mi := &file_todo_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -372,7 +662,7 @@ func (x *GetTodoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTodoRequest.ProtoReflect.Descriptor instead.
 func (*GetTodoRequest) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{5}
+	return file_todo_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetTodoRequest) GetId() string {
@@ -391,7 +681,7 @@ type GetTodoResponse struct {
 
 func (x *GetTodoResponse) Reset() {
 	*x = GetTodoResponse{}
-	mi := &file_todo_proto_msgTypes[6]
+	mi := &file_todo_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -403,7 +693,7 @@ func (x *GetTodoResponse) String() string {
 func (*GetTodoResponse) ProtoMessage() {}
 
 func (x *GetTodoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[6]
+	mi := &file_todo_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -416,7 +706,7 @@ func (x *GetTodoResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTodoResponse.ProtoReflect.Descriptor instead.
 func (*GetTodoResponse) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{6}
+	return file_todo_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetTodoResponse) GetTodo() *Todo {
@@ -427,16 +717,19 @@ func (x *GetTodoResponse) GetTodo() *Todo {
 }
 
 type ListTodosRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Limit  int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// User ID filters; "me" resolves to the calling user.
+	CreatedBy     string `protobuf:"bytes,3,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	AssignedTo    string `protobuf:"bytes,4,opt,name=assigned_to,json=assignedTo,proto3" json:"assigned_to,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListTodosRequest) Reset() {
 	*x = ListTodosRequest{}
-	mi := &file_todo_proto_msgTypes[7]
+	mi := &file_todo_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -448,7 +741,7 @@ func (x *ListTodosRequest) String() string {
 func (*ListTodosRequest) ProtoMessage() {}
 
 func (x *ListTodosRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[7]
+	mi := &file_todo_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -461,7 +754,7 @@ func (x *ListTodosRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListTodosRequest.ProtoReflect.Descriptor instead.
 func (*ListTodosRequest) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{7}
+	return file_todo_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ListTodosRequest) GetLimit() int32 {
@@ -478,6 +771,20 @@ func (x *ListTodosRequest) GetOffset() int32 {
 	return 0
 }
 
+func (x *ListTodosRequest) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *ListTodosRequest) GetAssignedTo() string {
+	if x != nil {
+		return x.AssignedTo
+	}
+	return ""
+}
+
 type ListTodosResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Todos         []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"`
@@ -487,7 +794,7 @@ type ListTodosResponse struct {
 
 func (x *ListTodosResponse) Reset() {
 	*x = ListTodosResponse{}
-	mi := &file_todo_proto_msgTypes[8]
+	mi := &file_todo_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -499,7 +806,7 @@ func (x *ListTodosResponse) String() string {
 func (*ListTodosResponse) ProtoMessage() {}
 
 func (x *ListTodosResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[8]
+	mi := &file_todo_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -512,7 +819,7 @@ func (x *ListTodosResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListTodosResponse.ProtoReflect.Descriptor instead.
 func (*ListTodosResponse) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{8}
+	return file_todo_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ListTodosResponse) GetTodos() []*Todo {
@@ -523,18 +830,27 @@ func (x *ListTodosResponse) GetTodos() []*Todo {
 }
 
 type UpdateTodoRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Status        Status                 `protobuf:"varint,4,opt,name=status,proto3,enum=todo.v1.Status" json:"status,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title       string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Status      Status                 `protobuf:"varint,4,opt,name=status,proto3,enum=todo.v1.Status" json:"status,omitempty"`
+	// Replaces the assignee list when non-empty.
+	Assignees []string `protobuf:"bytes,5,rep,name=assignees,proto3" json:"assignees,omitempty"`
+	// Removes every assignee; cannot be combined with assignees.
+	ClearAssignees bool `protobuf:"varint,6,opt,name=clear_assignees,json=clearAssignees,proto3" json:"clear_assignees,omitempty"`
+	// Fields to update: "title", "description", "status" and "assignees".
+	// When set, exactly these fields are written, so empty values clear them,
+	// and clear_assignees must not be used. When unset, empty fields are left
+	// unchanged.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,7,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateTodoRequest) Reset() {
 	*x = UpdateTodoRequest{}
-	mi := &file_todo_proto_msgTypes[9]
+	mi := &file_todo_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -546,7 +862,7 @@ func (x *UpdateTodoRequest) String() string {
 func (*UpdateTodoRequest) ProtoMessage() {}
 
 func (x *UpdateTodoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[9]
+	mi := &file_todo_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -559,7 +875,7 @@ func (x *UpdateTodoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateTodoRequest.ProtoReflect.Descriptor instead.
 func (*UpdateTodoRequest) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{9}
+	return file_todo_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *UpdateTodoRequest) GetId() string {
@@ -590,6 +906,27 @@ func (x *UpdateTodoRequest) GetStatus() Status {
 	return Status_STATUS_UNSPECIFIED
 }
 
+func (x *UpdateTodoRequest) GetAssignees() []string {
+	if x != nil {
+		return x.Assignees
+	}
+	return nil
+}
+
+func (x *UpdateTodoRequest) GetClearAssignees() bool {
+	if x != nil {
+		return x.ClearAssignees
+	}
+	return false
+}
+
+func (x *UpdateTodoRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
 type UpdateTodoResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Todo          *Todo                  `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
@@ -599,7 +936,7 @@ type UpdateTodoResponse struct {
 
 func (x *UpdateTodoResponse) Reset() {
 	*x = UpdateTodoResponse{}
-	mi := &file_todo_proto_msgTypes[10]
+	mi := &file_todo_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -611,7 +948,7 @@ func (x *UpdateTodoResponse) String() string {
 func (*UpdateTodoResponse) ProtoMessage() {}
 
 func (x *UpdateTodoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[10]
+	mi := &file_todo_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -624,7 +961,7 @@ func (x *UpdateTodoResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateTodoResponse.ProtoReflect.Descriptor instead.
 func (*UpdateTodoResponse) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{10}
+	return file_todo_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *UpdateTodoResponse) GetTodo() *Todo {
@@ -634,28 +971,937 @@ func (x *UpdateTodoResponse) GetTodo() *Todo {
 	return nil
 }
 
-type DeleteTodoRequest struct {
+type UpsertTodoRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Status        Status                 `protobuf:"varint,4,opt,name=status,proto3,enum=todo.v1.Status" json:"status,omitempty"`
+	Assignees     []string               `protobuf:"bytes,5,rep,name=assignees,proto3" json:"assignees,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteTodoRequest) Reset() {
-	*x = DeleteTodoRequest{}
-	mi := &file_todo_proto_msgTypes[11]
+func (x *UpsertTodoRequest) Reset() {
+	*x = UpsertTodoRequest{}
+	mi := &file_todo_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteTodoRequest) String() string {
+func (x *UpsertTodoRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteTodoRequest) ProtoMessage() {}
+func (*UpsertTodoRequest) ProtoMessage() {}
 
-func (x *DeleteTodoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[11]
+func (x *UpsertTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertTodoRequest.ProtoReflect.Descriptor instead.
+func (*UpsertTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpsertTodoRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpsertTodoRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpsertTodoRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpsertTodoRequest) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *UpsertTodoRequest) GetAssignees() []string {
+	if x != nil {
+		return x.Assignees
+	}
+	return nil
+}
+
+type UpsertTodoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Todo          *Todo                  `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	Created       bool                   `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertTodoResponse) Reset() {
+	*x = UpsertTodoResponse{}
+	mi := &file_todo_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertTodoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertTodoResponse) ProtoMessage() {}
+
+func (x *UpsertTodoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertTodoResponse.ProtoReflect.Descriptor instead.
+func (*UpsertTodoResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpsertTodoResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+func (x *UpsertTodoResponse) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+type DeleteTodoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTodoRequest) Reset() {
+	*x = DeleteTodoRequest{}
+	mi := &file_todo_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTodoRequest) ProtoMessage() {}
+
+func (x *DeleteTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTodoRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeleteTodoRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteTodoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTodoResponse) Reset() {
+	*x = DeleteTodoResponse{}
+	mi := &file_todo_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTodoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTodoResponse) ProtoMessage() {}
+
+func (x *DeleteTodoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTodoResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTodoResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DeleteTodoResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type Grant struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	TodoId      string                 `protobuf:"bytes,1,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	SubjectType SubjectType            `protobuf:"varint,2,opt,name=subject_type,json=subjectType,proto3,enum=todo.v1.SubjectType" json:"subject_type,omitempty"`
+	// A user UUID or a group name.
+	SubjectId     string     `protobuf:"bytes,3,opt,name=subject_id,json=subjectId,proto3" json:"subject_id,omitempty"`
+	Permission    Permission `protobuf:"varint,4,opt,name=permission,proto3,enum=todo.v1.Permission" json:"permission,omitempty"`
+	CreatedAtUnix int64      `protobuf:"varint,5,opt,name=created_at_unix,json=createdAtUnix,proto3" json:"created_at_unix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Grant) Reset() {
+	*x = Grant{}
+	mi := &file_todo_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Grant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Grant) ProtoMessage() {}
+
+func (x *Grant) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Grant.ProtoReflect.Descriptor instead.
+func (*Grant) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Grant) GetTodoId() string {
+	if x != nil {
+		return x.TodoId
+	}
+	return ""
+}
+
+func (x *Grant) GetSubjectType() SubjectType {
+	if x != nil {
+		return x.SubjectType
+	}
+	return SubjectType_SUBJECT_TYPE_UNSPECIFIED
+}
+
+func (x *Grant) GetSubjectId() string {
+	if x != nil {
+		return x.SubjectId
+	}
+	return ""
+}
+
+func (x *Grant) GetPermission() Permission {
+	if x != nil {
+		return x.Permission
+	}
+	return Permission_PERMISSION_UNSPECIFIED
+}
+
+func (x *Grant) GetCreatedAtUnix() int64 {
+	if x != nil {
+		return x.CreatedAtUnix
+	}
+	return 0
+}
+
+type ShareTodoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TodoId        string                 `protobuf:"bytes,1,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	SubjectType   SubjectType            `protobuf:"varint,2,opt,name=subject_type,json=subjectType,proto3,enum=todo.v1.SubjectType" json:"subject_type,omitempty"`
+	SubjectId     string                 `protobuf:"bytes,3,opt,name=subject_id,json=subjectId,proto3" json:"subject_id,omitempty"`
+	Permission    Permission             `protobuf:"varint,4,opt,name=permission,proto3,enum=todo.v1.Permission" json:"permission,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShareTodoRequest) Reset() {
+	*x = ShareTodoRequest{}
+	mi := &file_todo_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareTodoRequest) ProtoMessage() {}
+
+func (x *ShareTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareTodoRequest.ProtoReflect.Descriptor instead.
+func (*ShareTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ShareTodoRequest) GetTodoId() string {
+	if x != nil {
+		return x.TodoId
+	}
+	return ""
+}
+
+func (x *ShareTodoRequest) GetSubjectType() SubjectType {
+	if x != nil {
+		return x.SubjectType
+	}
+	return SubjectType_SUBJECT_TYPE_UNSPECIFIED
+}
+
+func (x *ShareTodoRequest) GetSubjectId() string {
+	if x != nil {
+		return x.SubjectId
+	}
+	return ""
+}
+
+func (x *ShareTodoRequest) GetPermission() Permission {
+	if x != nil {
+		return x.Permission
+	}
+	return Permission_PERMISSION_UNSPECIFIED
+}
+
+type ShareTodoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Grant         *Grant                 `protobuf:"bytes,1,opt,name=grant,proto3" json:"grant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShareTodoResponse) Reset() {
+	*x = ShareTodoResponse{}
+	mi := &file_todo_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareTodoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareTodoResponse) ProtoMessage() {}
+
+func (x *ShareTodoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareTodoResponse.ProtoReflect.Descriptor instead.
+func (*ShareTodoResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ShareTodoResponse) GetGrant() *Grant {
+	if x != nil {
+		return x.Grant
+	}
+	return nil
+}
+
+type UnshareTodoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TodoId        string                 `protobuf:"bytes,1,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	SubjectType   SubjectType            `protobuf:"varint,2,opt,name=subject_type,json=subjectType,proto3,enum=todo.v1.SubjectType" json:"subject_type,omitempty"`
+	SubjectId     string                 `protobuf:"bytes,3,opt,name=subject_id,json=subjectId,proto3" json:"subject_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnshareTodoRequest) Reset() {
+	*x = UnshareTodoRequest{}
+	mi := &file_todo_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnshareTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnshareTodoRequest) ProtoMessage() {}
+
+func (x *UnshareTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnshareTodoRequest.ProtoReflect.Descriptor instead.
+func (*UnshareTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UnshareTodoRequest) GetTodoId() string {
+	if x != nil {
+		return x.TodoId
+	}
+	return ""
+}
+
+func (x *UnshareTodoRequest) GetSubjectType() SubjectType {
+	if x != nil {
+		return x.SubjectType
+	}
+	return SubjectType_SUBJECT_TYPE_UNSPECIFIED
+}
+
+func (x *UnshareTodoRequest) GetSubjectId() string {
+	if x != nil {
+		return x.SubjectId
+	}
+	return ""
+}
+
+type UnshareTodoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnshareTodoResponse) Reset() {
+	*x = UnshareTodoResponse{}
+	mi := &file_todo_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnshareTodoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnshareTodoResponse) ProtoMessage() {}
+
+func (x *UnshareTodoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnshareTodoResponse.ProtoReflect.Descriptor instead.
+func (*UnshareTodoResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UnshareTodoResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type ListTodoGrantsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TodoId        string                 `protobuf:"bytes,1,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTodoGrantsRequest) Reset() {
+	*x = ListTodoGrantsRequest{}
+	mi := &file_todo_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTodoGrantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTodoGrantsRequest) ProtoMessage() {}
+
+func (x *ListTodoGrantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTodoGrantsRequest.ProtoReflect.Descriptor instead.
+func (*ListTodoGrantsRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListTodoGrantsRequest) GetTodoId() string {
+	if x != nil {
+		return x.TodoId
+	}
+	return ""
+}
+
+type ListTodoGrantsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Grants        []*Grant               `protobuf:"bytes,1,rep,name=grants,proto3" json:"grants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTodoGrantsResponse) Reset() {
+	*x = ListTodoGrantsResponse{}
+	mi := &file_todo_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTodoGrantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTodoGrantsResponse) ProtoMessage() {}
+
+func (x *ListTodoGrantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTodoGrantsResponse.ProtoReflect.Descriptor instead.
+func (*ListTodoGrantsResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListTodoGrantsResponse) GetGrants() []*Grant {
+	if x != nil {
+		return x.Grants
+	}
+	return nil
+}
+
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TenantId      string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAtUnix int64                  `protobuf:"varint,5,opt,name=created_at_unix,json=createdAtUnix,proto3" json:"created_at_unix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_todo_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *User) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *User) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *User) GetCreatedAtUnix() int64 {
+	if x != nil {
+		return x.CreatedAtUnix
+	}
+	return 0
+}
+
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_todo_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CreateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserResponse) Reset() {
+	*x = CreateUserResponse{}
+	mi := &file_todo_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserResponse) ProtoMessage() {}
+
+func (x *CreateUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserResponse.ProtoReflect.Descriptor instead.
+func (*CreateUserResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CreateUserResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_todo_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserResponse) Reset() {
+	*x = GetUserResponse{}
+	mi := &file_todo_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserResponse) ProtoMessage() {}
+
+func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserResponse.ProtoReflect.Descriptor instead.
+func (*GetUserResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetUserResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_todo_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListUsersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_todo_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -666,40 +1912,181 @@ func (x *DeleteTodoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteTodoRequest.ProtoReflect.Descriptor instead.
-func (*DeleteTodoRequest) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *DeleteTodoRequest) GetId() string {
+func (x *ListUsersResponse) GetUsers() []*User {
 	if x != nil {
-		return x.Id
+		return x.Users
+	}
+	return nil
+}
+
+type WatchTodosRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Event types to receive; empty means all.
+	Types []TodoEventType `protobuf:"varint,1,rep,packed,name=types,proto3,enum=todo.v1.TodoEventType" json:"types,omitempty"`
+	// User ID filters; "me" resolves to the calling user.
+	CreatedBy  string `protobuf:"bytes,2,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	AssignedTo string `protobuf:"bytes,3,opt,name=assigned_to,json=assignedTo,proto3" json:"assigned_to,omitempty"`
+	// resume_token of the last event received, to replay missed events.
+	ResumeToken   string `protobuf:"bytes,4,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTodosRequest) Reset() {
+	*x = WatchTodosRequest{}
+	mi := &file_todo_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTodosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTodosRequest) ProtoMessage() {}
+
+func (x *WatchTodosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTodosRequest.ProtoReflect.Descriptor instead.
+func (*WatchTodosRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *WatchTodosRequest) GetTypes() []TodoEventType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *WatchTodosRequest) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
 	}
 	return ""
 }
 
-type DeleteTodoResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+func (x *WatchTodosRequest) GetAssignedTo() string {
+	if x != nil {
+		return x.AssignedTo
+	}
+	return ""
+}
+
+func (x *WatchTodosRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+type TodoEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  TodoEventType          `protobuf:"varint,1,opt,name=type,proto3,enum=todo.v1.TodoEventType" json:"type,omitempty"`
+	// State after the change, or the last state for deletions.
+	Todo           *Todo  `protobuf:"bytes,2,opt,name=todo,proto3" json:"todo,omitempty"`
+	ResumeToken    string `protobuf:"bytes,3,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	OccurredAtUnix int64  `protobuf:"varint,4,opt,name=occurred_at_unix,json=occurredAtUnix,proto3" json:"occurred_at_unix,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TodoEvent) Reset() {
+	*x = TodoEvent{}
+	mi := &file_todo_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodoEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodoEvent) ProtoMessage() {}
+
+func (x *TodoEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodoEvent.ProtoReflect.Descriptor instead.
+func (*TodoEvent) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *TodoEvent) GetType() TodoEventType {
+	if x != nil {
+		return x.Type
+	}
+	return TodoEventType_TODO_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *TodoEvent) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+func (x *TodoEvent) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *TodoEvent) GetOccurredAtUnix() int64 {
+	if x != nil {
+		return x.OccurredAtUnix
+	}
+	return 0
+}
+
+type ImportTodosRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Items to create. Clients may send any number of items per message.
+	Items         []*CreateTodoRequest `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteTodoResponse) Reset() {
-	*x = DeleteTodoResponse{}
-	mi := &file_todo_proto_msgTypes[12]
+func (x *ImportTodosRequest) Reset() {
+	*x = ImportTodosRequest{}
+	mi := &file_todo_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteTodoResponse) String() string {
+func (x *ImportTodosRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteTodoResponse) ProtoMessage() {}
+func (*ImportTodosRequest) ProtoMessage() {}
 
-func (x *DeleteTodoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[12]
+func (x *ImportTodosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -710,16 +2097,192 @@ func (x *DeleteTodoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteTodoResponse.ProtoReflect.Descriptor instead.
-func (*DeleteTodoResponse) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ImportTodosRequest.ProtoReflect.Descriptor instead.
+func (*ImportTodosRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *DeleteTodoResponse) GetDeleted() bool {
+func (x *ImportTodosRequest) GetItems() []*CreateTodoRequest {
 	if x != nil {
-		return x.Deleted
+		return x.Items
 	}
-	return false
+	return nil
+}
+
+type ImportError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the item in the whole stream, counting from 1.
+	Line int64 `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"`
+	// Error catalog code, such as VALIDATION_FAILED.
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportError) Reset() {
+	*x = ImportError{}
+	mi := &file_todo_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportError) ProtoMessage() {}
+
+func (x *ImportError) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportError.ProtoReflect.Descriptor instead.
+func (*ImportError) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ImportError) GetLine() int64 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *ImportError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ImportError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ImportTodosResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Created int64                  `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
+	Failed  int64                  `protobuf:"varint,2,opt,name=failed,proto3" json:"failed,omitempty"`
+	// The first 1000 failures, in stream order.
+	Errors        []*ImportError `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportTodosResponse) Reset() {
+	*x = ImportTodosResponse{}
+	mi := &file_todo_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportTodosResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportTodosResponse) ProtoMessage() {}
+
+func (x *ImportTodosResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportTodosResponse.ProtoReflect.Descriptor instead.
+func (*ImportTodosResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ImportTodosResponse) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ImportTodosResponse) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ImportTodosResponse) GetErrors() []*ImportError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type ExportTodosRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// User ID filters; "me" resolves to the calling user.
+	CreatedBy     string `protobuf:"bytes,1,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	AssignedTo    string `protobuf:"bytes,2,opt,name=assigned_to,json=assignedTo,proto3" json:"assigned_to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportTodosRequest) Reset() {
+	*x = ExportTodosRequest{}
+	mi := &file_todo_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportTodosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTodosRequest) ProtoMessage() {}
+
+func (x *ExportTodosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTodosRequest.ProtoReflect.Descriptor instead.
+func (*ExportTodosRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ExportTodosRequest) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *ExportTodosRequest) GetAssignedTo() string {
+	if x != nil {
+		return x.AssignedTo
+	}
+	return ""
 }
 
 var File_todo_proto protoreflect.FileDescriptor
@@ -727,47 +2290,169 @@ var File_todo_proto protoreflect.FileDescriptor
 const file_todo_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"todo.proto\x12\atodo.v1\"\xc7\x01\n" +
+	"todo.proto\x12\atodo.v1\x1a google/protobuf/field_mask.proto\x1a\x17google/rpc/status.proto\"\xa1\x02\n" +
 	"\x04Todo\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
 	"\vdescription\x18\x03 \x01(\tR\vdescription\x12'\n" +
 	"\x06status\x18\x04 \x01(\x0e2\x0f.todo.v1.StatusR\x06status\x12&\n" +
 	"\x0fcreated_at_unix\x18\x05 \x01(\x03R\rcreatedAtUnix\x12&\n" +
-	"\x0fupdated_at_unix\x18\x06 \x01(\x03R\rupdatedAtUnix\"K\n" +
+	"\x0fupdated_at_unix\x18\x06 \x01(\x03R\rupdatedAtUnix\x12\x1b\n" +
+	"\ttenant_id\x18\a \x01(\tR\btenantId\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\b \x01(\tR\tcreatedBy\x12\x1c\n" +
+	"\tassignees\x18\t \x03(\tR\tassignees\"y\n" +
 	"\x11CreateTodoRequest\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\"7\n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x1c\n" +
+	"\tassignees\x18\x04 \x03(\tR\tassignees\"7\n" +
 	"\x12CreateTodoResponse\x12!\n" +
-	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"J\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"s\n" +
 	"\x16BulkCreateTodosRequest\x120\n" +
-	"\x05items\x18\x01 \x03(\v2\x1a.todo.v1.CreateTodoRequestR\x05items\">\n" +
+	"\x05items\x18\x01 \x03(\v2\x1a.todo.v1.CreateTodoRequestR\x05items\x12'\n" +
+	"\x0fpartial_success\x18\x02 \x01(\bR\x0epartialSuccess\"\x91\x01\n" +
+	"\x10BulkCreateResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12!\n" +
+	"\x04todo\x18\x02 \x01(\v2\r.todo.v1.TodoR\x04todo\x12\x18\n" +
+	"\x05error\x18\x03 \x01(\tB\x02\x18\x01R\x05error\x12*\n" +
+	"\x06status\x18\x04 \x01(\v2\x12.google.rpc.StatusR\x06status\"s\n" +
 	"\x17BulkCreateTodosResponse\x12#\n" +
-	"\x05todos\x18\x01 \x03(\v2\r.todo.v1.TodoR\x05todos\" \n" +
+	"\x05todos\x18\x01 \x03(\v2\r.todo.v1.TodoR\x05todos\x123\n" +
+	"\aresults\x18\x02 \x03(\v2\x19.todo.v1.BulkCreateResultR\aresults\" \n" +
 	"\x0eGetTodoRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"4\n" +
 	"\x0fGetTodoResponse\x12!\n" +
-	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"@\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"\x80\x01\n" +
 	"\x10ListTodosRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\"8\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x03 \x01(\tR\tcreatedBy\x12\x1f\n" +
+	"\vassigned_to\x18\x04 \x01(\tR\n" +
+	"assignedTo\"8\n" +
 	"\x11ListTodosResponse\x12#\n" +
-	"\x05todos\x18\x01 \x03(\v2\r.todo.v1.TodoR\x05todos\"\x84\x01\n" +
+	"\x05todos\x18\x01 \x03(\v2\r.todo.v1.TodoR\x05todos\"\x88\x02\n" +
 	"\x11UpdateTodoRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
 	"\vdescription\x18\x03 \x01(\tR\vdescription\x12'\n" +
-	"\x06status\x18\x04 \x01(\x0e2\x0f.todo.v1.StatusR\x06status\"7\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x0f.todo.v1.StatusR\x06status\x12\x1c\n" +
+	"\tassignees\x18\x05 \x03(\tR\tassignees\x12'\n" +
+	"\x0fclear_assignees\x18\x06 \x01(\bR\x0eclearAssignees\x12;\n" +
+	"\vupdate_mask\x18\a \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"7\n" +
 	"\x12UpdateTodoResponse\x12!\n" +
-	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"#\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\"\xa2\x01\n" +
+	"\x11UpsertTodoRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12'\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x0f.todo.v1.StatusR\x06status\x12\x1c\n" +
+	"\tassignees\x18\x05 \x03(\tR\tassignees\"Q\n" +
+	"\x12UpsertTodoResponse\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v1.TodoR\x04todo\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\bR\acreated\"#\n" +
 	"\x11DeleteTodoRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\".\n" +
 	"\x12DeleteTodoResponse\x12\x18\n" +
-	"\adeleted\x18\x01 \x01(\bR\adeleted*E\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\"\xd5\x01\n" +
+	"\x05Grant\x12\x17\n" +
+	"\atodo_id\x18\x01 \x01(\tR\x06todoId\x127\n" +
+	"\fsubject_type\x18\x02 \x01(\x0e2\x14.todo.v1.SubjectTypeR\vsubjectType\x12\x1d\n" +
+	"\n" +
+	"subject_id\x18\x03 \x01(\tR\tsubjectId\x123\n" +
+	"\n" +
+	"permission\x18\x04 \x01(\x0e2\x13.todo.v1.PermissionR\n" +
+	"permission\x12&\n" +
+	"\x0fcreated_at_unix\x18\x05 \x01(\x03R\rcreatedAtUnix\"\xb8\x01\n" +
+	"\x10ShareTodoRequest\x12\x17\n" +
+	"\atodo_id\x18\x01 \x01(\tR\x06todoId\x127\n" +
+	"\fsubject_type\x18\x02 \x01(\x0e2\x14.todo.v1.SubjectTypeR\vsubjectType\x12\x1d\n" +
+	"\n" +
+	"subject_id\x18\x03 \x01(\tR\tsubjectId\x123\n" +
+	"\n" +
+	"permission\x18\x04 \x01(\x0e2\x13.todo.v1.PermissionR\n" +
+	"permission\"9\n" +
+	"\x11ShareTodoResponse\x12$\n" +
+	"\x05grant\x18\x01 \x01(\v2\x0e.todo.v1.GrantR\x05grant\"\x85\x01\n" +
+	"\x12UnshareTodoRequest\x12\x17\n" +
+	"\atodo_id\x18\x01 \x01(\tR\x06todoId\x127\n" +
+	"\fsubject_type\x18\x02 \x01(\x0e2\x14.todo.v1.SubjectTypeR\vsubjectType\x12\x1d\n" +
+	"\n" +
+	"subject_id\x18\x03 \x01(\tR\tsubjectId\"/\n" +
+	"\x13UnshareTodoResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\"0\n" +
+	"\x15ListTodoGrantsRequest\x12\x17\n" +
+	"\atodo_id\x18\x01 \x01(\tR\x06todoId\"@\n" +
+	"\x16ListTodoGrantsResponse\x12&\n" +
+	"\x06grants\x18\x01 \x03(\v2\x0e.todo.v1.GrantR\x06grants\"\x85\x01\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12&\n" +
+	"\x0fcreated_at_unix\x18\x05 \x01(\x03R\rcreatedAtUnix\"=\n" +
+	"\x11CreateUserRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"7\n" +
+	"\x12CreateUserResponse\x12!\n" +
+	"\x04user\x18\x01 \x01(\v2\r.todo.v1.UserR\x04user\" \n" +
+	"\x0eGetUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"4\n" +
+	"\x0fGetUserResponse\x12!\n" +
+	"\x04user\x18\x01 \x01(\v2\r.todo.v1.UserR\x04user\"@\n" +
+	"\x10ListUsersRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\"8\n" +
+	"\x11ListUsersResponse\x12#\n" +
+	"\x05users\x18\x01 \x03(\v2\r.todo.v1.UserR\x05users\"\xa4\x01\n" +
+	"\x11WatchTodosRequest\x12,\n" +
+	"\x05types\x18\x01 \x03(\x0e2\x16.todo.v1.TodoEventTypeR\x05types\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x02 \x01(\tR\tcreatedBy\x12\x1f\n" +
+	"\vassigned_to\x18\x03 \x01(\tR\n" +
+	"assignedTo\x12!\n" +
+	"\fresume_token\x18\x04 \x01(\tR\vresumeToken\"\xa7\x01\n" +
+	"\tTodoEvent\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.todo.v1.TodoEventTypeR\x04type\x12!\n" +
+	"\x04todo\x18\x02 \x01(\v2\r.todo.v1.TodoR\x04todo\x12!\n" +
+	"\fresume_token\x18\x03 \x01(\tR\vresumeToken\x12(\n" +
+	"\x10occurred_at_unix\x18\x04 \x01(\x03R\x0eoccurredAtUnix\"F\n" +
+	"\x12ImportTodosRequest\x120\n" +
+	"\x05items\x18\x01 \x03(\v2\x1a.todo.v1.CreateTodoRequestR\x05items\"O\n" +
+	"\vImportError\x12\x12\n" +
+	"\x04line\x18\x01 \x01(\x03R\x04line\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"u\n" +
+	"\x13ImportTodosResponse\x12\x18\n" +
+	"\acreated\x18\x01 \x01(\x03R\acreated\x12\x16\n" +
+	"\x06failed\x18\x02 \x01(\x03R\x06failed\x12,\n" +
+	"\x06errors\x18\x03 \x03(\v2\x14.todo.v1.ImportErrorR\x06errors\"T\n" +
+	"\x12ExportTodosRequest\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x01 \x01(\tR\tcreatedBy\x12\x1f\n" +
+	"\vassigned_to\x18\x02 \x01(\tR\n" +
+	"assignedTo*E\n" +
 	"\x06Status\x12\x16\n" +
 	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x12\n" +
 	"\x0eSTATUS_PENDING\x10\x01\x12\x0f\n" +
-	"\vSTATUS_DONE\x10\x022\xba\x03\n" +
+	"\vSTATUS_DONE\x10\x02*l\n" +
+	"\n" +
+	"Permission\x12\x1a\n" +
+	"\x16PERMISSION_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11PERMISSION_VIEWER\x10\x01\x12\x15\n" +
+	"\x11PERMISSION_EDITOR\x10\x02\x12\x14\n" +
+	"\x10PERMISSION_OWNER\x10\x03*Z\n" +
+	"\vSubjectType\x12\x1c\n" +
+	"\x18SUBJECT_TYPE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11SUBJECT_TYPE_USER\x10\x01\x12\x16\n" +
+	"\x12SUBJECT_TYPE_GROUP\x10\x02*\x87\x01\n" +
+	"\rTodoEventType\x12\x1f\n" +
+	"\x1bTODO_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17TODO_EVENT_TYPE_CREATED\x10\x01\x12\x1b\n" +
+	"\x17TODO_EVENT_TYPE_UPDATED\x10\x02\x12\x1b\n" +
+	"\x17TODO_EVENT_TYPE_DELETED\x10\x032\xab\a\n" +
 	"\vTodoService\x12E\n" +
 	"\n" +
 	"CreateTodo\x12\x1a.todo.v1.CreateTodoRequest\x1a\x1b.todo.v1.CreateTodoResponse\x12T\n" +
@@ -777,7 +2462,21 @@ const file_todo_proto_rawDesc = "" +
 	"\n" +
 	"UpdateTodo\x12\x1a.todo.v1.UpdateTodoRequest\x1a\x1b.todo.v1.UpdateTodoResponse\x12E\n" +
 	"\n" +
-	"DeleteTodo\x12\x1a.todo.v1.DeleteTodoRequest\x1a\x1b.todo.v1.DeleteTodoResponseB?Z=github.com/fuzail-ahmed/codex-test/shared/gen/todo/v1;todo_v1b\x06proto3"
+	"UpsertTodo\x12\x1a.todo.v1.UpsertTodoRequest\x1a\x1b.todo.v1.UpsertTodoResponse\x12E\n" +
+	"\n" +
+	"DeleteTodo\x12\x1a.todo.v1.DeleteTodoRequest\x1a\x1b.todo.v1.DeleteTodoResponse\x12B\n" +
+	"\tShareTodo\x12\x19.todo.v1.ShareTodoRequest\x1a\x1a.todo.v1.ShareTodoResponse\x12H\n" +
+	"\vUnshareTodo\x12\x1b.todo.v1.UnshareTodoRequest\x1a\x1c.todo.v1.UnshareTodoResponse\x12Q\n" +
+	"\x0eListTodoGrants\x12\x1e.todo.v1.ListTodoGrantsRequest\x1a\x1f.todo.v1.ListTodoGrantsResponse\x12>\n" +
+	"\n" +
+	"WatchTodos\x12\x1a.todo.v1.WatchTodosRequest\x1a\x12.todo.v1.TodoEvent0\x01\x12J\n" +
+	"\vImportTodos\x12\x1b.todo.v1.ImportTodosRequest\x1a\x1c.todo.v1.ImportTodosResponse(\x01\x12;\n" +
+	"\vExportTodos\x12\x1b.todo.v1.ExportTodosRequest\x1a\r.todo.v1.Todo0\x012\xd6\x01\n" +
+	"\vUserService\x12E\n" +
+	"\n" +
+	"CreateUser\x12\x1a.todo.v1.CreateUserRequest\x1a\x1b.todo.v1.CreateUserResponse\x12<\n" +
+	"\aGetUser\x12\x17.todo.v1.GetUserRequest\x1a\x18.todo.v1.GetUserResponse\x12B\n" +
+	"\tListUsers\x12\x19.todo.v1.ListUsersRequest\x1a\x1a.todo.v1.ListUsersResponseB?Z=github.com/fuzail-ahmed/codex-test/shared/gen/todo/v1;todo_v1b\x06proto3"
 
 var (
 	file_todo_proto_rawDescOnce sync.Once
@@ -791,50 +2490,119 @@ func file_todo_proto_rawDescGZIP() []byte {
 	return file_todo_proto_rawDescData
 }
 
-var file_todo_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_todo_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
 var file_todo_proto_goTypes = []any{
 	(Status)(0),                     // 0: todo.v1.Status
-	(*Todo)(nil),                    // 1: todo.v1.Todo
-	(*CreateTodoRequest)(nil),       // 2: todo.v1.CreateTodoRequest
-	(*CreateTodoResponse)(nil),      // 3: todo.v1.CreateTodoResponse
-	(*BulkCreateTodosRequest)(nil),  // 4: todo.v1.BulkCreateTodosRequest
-	(*BulkCreateTodosResponse)(nil), // 5: todo.v1.BulkCreateTodosResponse
-	(*GetTodoRequest)(nil),          // 6: todo.v1.GetTodoRequest
-	(*GetTodoResponse)(nil),         // 7: todo.v1.GetTodoResponse
-	(*ListTodosRequest)(nil),        // 8: todo.v1.ListTodosRequest
-	(*ListTodosResponse)(nil),       // 9: todo.v1.ListTodosResponse
-	(*UpdateTodoRequest)(nil),       // 10: todo.v1.UpdateTodoRequest
-	(*UpdateTodoResponse)(nil),      // 11: todo.v1.UpdateTodoResponse
-	(*DeleteTodoRequest)(nil),       // 12: todo.v1.DeleteTodoRequest
-	(*DeleteTodoResponse)(nil),      // 13: todo.v1.DeleteTodoResponse
+	(Permission)(0),                 // 1: todo.v1.Permission
+	(SubjectType)(0),                // 2: todo.v1.SubjectType
+	(TodoEventType)(0),              // 3: todo.v1.TodoEventType
+	(*Todo)(nil),                    // 4: todo.v1.Todo
+	(*CreateTodoRequest)(nil),       // 5: todo.v1.CreateTodoRequest
+	(*CreateTodoResponse)(nil),      // 6: todo.v1.CreateTodoResponse
+	(*BulkCreateTodosRequest)(nil),  // 7: todo.v1.BulkCreateTodosRequest
+	(*BulkCreateResult)(nil),        // 8: todo.v1.BulkCreateResult
+	(*BulkCreateTodosResponse)(nil), // 9: todo.v1.BulkCreateTodosResponse
+	(*GetTodoRequest)(nil),          // 10: todo.v1.GetTodoRequest
+	(*GetTodoResponse)(nil),         // 11: todo.v1.GetTodoResponse
+	(*ListTodosRequest)(nil),        // 12: todo.v1.ListTodosRequest
+	(*ListTodosResponse)(nil),       // 13: todo.v1.ListTodosResponse
+	(*UpdateTodoRequest)(nil),       // 14: todo.v1.UpdateTodoRequest
+	(*UpdateTodoResponse)(nil),      // 15: todo.v1.UpdateTodoResponse
+	(*UpsertTodoRequest)(nil),       // 16: todo.v1.UpsertTodoRequest
+	(*UpsertTodoResponse)(nil),      // 17: todo.v1.UpsertTodoResponse
+	(*DeleteTodoRequest)(nil),       // 18: todo.v1.DeleteTodoRequest
+	(*DeleteTodoResponse)(nil),      // 19: todo.v1.DeleteTodoResponse
+	(*Grant)(nil),                   // 20: todo.v1.Grant
+	(*ShareTodoRequest)(nil),        // 21: todo.v1.ShareTodoRequest
+	(*ShareTodoResponse)(nil),       // 22: todo.v1.ShareTodoResponse
+	(*UnshareTodoRequest)(nil),      // 23: todo.v1.UnshareTodoRequest
+	(*UnshareTodoResponse)(nil),     // 24: todo.v1.UnshareTodoResponse
+	(*ListTodoGrantsRequest)(nil),   // 25: todo.v1.ListTodoGrantsRequest
+	(*ListTodoGrantsResponse)(nil),  // 26: todo.v1.ListTodoGrantsResponse
+	(*User)(nil),                    // 27: todo.v1.User
+	(*CreateUserRequest)(nil),       // 28: todo.v1.CreateUserRequest
+	(*CreateUserResponse)(nil),      // 29: todo.v1.CreateUserResponse
+	(*GetUserRequest)(nil),          // 30: todo.v1.GetUserRequest
+	(*GetUserResponse)(nil),         // 31: todo.v1.GetUserResponse
+	(*ListUsersRequest)(nil),        // 32: todo.v1.ListUsersRequest
+	(*ListUsersResponse)(nil),       // 33: todo.v1.ListUsersResponse
+	(*WatchTodosRequest)(nil),       // 34: todo.v1.WatchTodosRequest
+	(*TodoEvent)(nil),               // 35: todo.v1.TodoEvent
+	(*ImportTodosRequest)(nil),      // 36: todo.v1.ImportTodosRequest
+	(*ImportError)(nil),             // 37: todo.v1.ImportError
+	(*ImportTodosResponse)(nil),     // 38: todo.v1.ImportTodosResponse
+	(*ExportTodosRequest)(nil),      // 39: todo.v1.ExportTodosRequest
+	(*status.Status)(nil),           // 40: google.rpc.Status
+	(*fieldmaskpb.FieldMask)(nil),   // 41: google.protobuf.FieldMask
 }
 var file_todo_proto_depIdxs = []int32{
 	0,  // 0: todo.v1.Todo.status:type_name -> todo.v1.Status
-	1,  // 1: todo.v1.CreateTodoResponse.todo:type_name -> todo.v1.Todo
-	2,  // 2: todo.v1.BulkCreateTodosRequest.items:type_name -> todo.v1.CreateTodoRequest
-	1,  // 3: todo.v1.BulkCreateTodosResponse.todos:type_name -> todo.v1.Todo
-	1,  // 4: todo.v1.GetTodoResponse.todo:type_name -> todo.v1.Todo
-	1,  // 5: todo.v1.ListTodosResponse.todos:type_name -> todo.v1.Todo
-	0,  // 6: todo.v1.UpdateTodoRequest.status:type_name -> todo.v1.Status
-	1,  // 7: todo.v1.UpdateTodoResponse.todo:type_name -> todo.v1.Todo
-	2,  // 8: todo.v1.TodoService.CreateTodo:input_type -> todo.v1.CreateTodoRequest
-	4,  // 9: todo.v1.TodoService.BulkCreateTodos:input_type -> todo.v1.BulkCreateTodosRequest
-	6,  // 10: todo.v1.TodoService.GetTodo:input_type -> todo.v1.GetTodoRequest
-	8,  // 11: todo.v1.TodoService.ListTodos:input_type -> todo.v1.ListTodosRequest
-	10, // 12: todo.v1.TodoService.UpdateTodo:input_type -> todo.v1.UpdateTodoRequest
-	12, // 13: todo.v1.TodoService.DeleteTodo:input_type -> todo.v1.DeleteTodoRequest
-	3,  // 14: todo.v1.TodoService.CreateTodo:output_type -> todo.v1.CreateTodoResponse
-	5,  // 15: todo.v1.TodoService.BulkCreateTodos:output_type -> todo.v1.BulkCreateTodosResponse
-	7,  // 16: todo.v1.TodoService.GetTodo:output_type -> todo.v1.GetTodoResponse
-	9,  // 17: todo.v1.TodoService.ListTodos:output_type -> todo.v1.ListTodosResponse
-	11, // 18: todo.v1.TodoService.UpdateTodo:output_type -> todo.v1.UpdateTodoResponse
-	13, // 19: todo.v1.TodoService.DeleteTodo:output_type -> todo.v1.DeleteTodoResponse
-	14, // [14:20] is the sub-list for method output_type
-	8,  // [8:14] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	4,  // 1: todo.v1.CreateTodoResponse.todo:type_name -> todo.v1.Todo
+	5,  // 2: todo.v1.BulkCreateTodosRequest.items:type_name -> todo.v1.CreateTodoRequest
+	4,  // 3: todo.v1.BulkCreateResult.todo:type_name -> todo.v1.Todo
+	40, // 4: todo.v1.BulkCreateResult.status:type_name -> google.rpc.Status
+	4,  // 5: todo.v1.BulkCreateTodosResponse.todos:type_name -> todo.v1.Todo
+	8,  // 6: todo.v1.BulkCreateTodosResponse.results:type_name -> todo.v1.BulkCreateResult
+	4,  // 7: todo.v1.GetTodoResponse.todo:type_name -> todo.v1.Todo
+	4,  // 8: todo.v1.ListTodosResponse.todos:type_name -> todo.v1.Todo
+	0,  // 9: todo.v1.UpdateTodoRequest.status:type_name -> todo.v1.Status
+	41, // 10: todo.v1.UpdateTodoRequest.update_mask:type_name -> google.protobuf.FieldMask
+	4,  // 11: todo.v1.UpdateTodoResponse.todo:type_name -> todo.v1.Todo
+	0,  // 12: todo.v1.UpsertTodoRequest.status:type_name -> todo.v1.Status
+	4,  // 13: todo.v1.UpsertTodoResponse.todo:type_name -> todo.v1.Todo
+	2,  // 14: todo.v1.Grant.subject_type:type_name -> todo.v1.SubjectType
+	1,  // 15: todo.v1.Grant.permission:type_name -> todo.v1.Permission
+	2,  // 16: todo.v1.ShareTodoRequest.subject_type:type_name -> todo.v1.SubjectType
+	1,  // 17: todo.v1.ShareTodoRequest.permission:type_name -> todo.v1.Permission
+	20, // 18: todo.v1.ShareTodoResponse.grant:type_name -> todo.v1.Grant
+	2,  // 19: todo.v1.UnshareTodoRequest.subject_type:type_name -> todo.v1.SubjectType
+	20, // 20: todo.v1.ListTodoGrantsResponse.grants:type_name -> todo.v1.Grant
+	27, // 21: todo.v1.CreateUserResponse.user:type_name -> todo.v1.User
+	27, // 22: todo.v1.GetUserResponse.user:type_name -> todo.v1.User
+	27, // 23: todo.v1.ListUsersResponse.users:type_name -> todo.v1.User
+	3,  // 24: todo.v1.WatchTodosRequest.types:type_name -> todo.v1.TodoEventType
+	3,  // 25: todo.v1.TodoEvent.type:type_name -> todo.v1.TodoEventType
+	4,  // 26: todo.v1.TodoEvent.todo:type_name -> todo.v1.Todo
+	5,  // 27: todo.v1.ImportTodosRequest.items:type_name -> todo.v1.CreateTodoRequest
+	37, // 28: todo.v1.ImportTodosResponse.errors:type_name -> todo.v1.ImportError
+	5,  // 29: todo.v1.TodoService.CreateTodo:input_type -> todo.v1.CreateTodoRequest
+	7,  // 30: todo.v1.TodoService.BulkCreateTodos:input_type -> todo.v1.BulkCreateTodosRequest
+	10, // 31: todo.v1.TodoService.GetTodo:input_type -> todo.v1.GetTodoRequest
+	12, // 32: todo.v1.TodoService.ListTodos:input_type -> todo.v1.ListTodosRequest
+	14, // 33: todo.v1.TodoService.UpdateTodo:input_type -> todo.v1.UpdateTodoRequest
+	16, // 34: todo.v1.TodoService.UpsertTodo:input_type -> todo.v1.UpsertTodoRequest
+	18, // 35: todo.v1.TodoService.DeleteTodo:input_type -> todo.v1.DeleteTodoRequest
+	21, // 36: todo.v1.TodoService.ShareTodo:input_type -> todo.v1.ShareTodoRequest
+	23, // 37: todo.v1.TodoService.UnshareTodo:input_type -> todo.v1.UnshareTodoRequest
+	25, // 38: todo.v1.TodoService.ListTodoGrants:input_type -> todo.v1.ListTodoGrantsRequest
+	34, // 39: todo.v1.TodoService.WatchTodos:input_type -> todo.v1.WatchTodosRequest
+	36, // 40: todo.v1.TodoService.ImportTodos:input_type -> todo.v1.ImportTodosRequest
+	39, // 41: todo.v1.TodoService.ExportTodos:input_type -> todo.v1.ExportTodosRequest
+	28, // 42: todo.v1.UserService.CreateUser:input_type -> todo.v1.CreateUserRequest
+	30, // 43: todo.v1.UserService.GetUser:input_type -> todo.v1.GetUserRequest
+	32, // 44: todo.v1.UserService.ListUsers:input_type -> todo.v1.ListUsersRequest
+	6,  // 45: todo.v1.TodoService.CreateTodo:output_type -> todo.v1.CreateTodoResponse
+	9,  // 46: todo.v1.TodoService.BulkCreateTodos:output_type -> todo.v1.BulkCreateTodosResponse
+	11, // 47: todo.v1.TodoService.GetTodo:output_type -> todo.v1.GetTodoResponse
+	13, // 48: todo.v1.TodoService.ListTodos:output_type -> todo.v1.ListTodosResponse
+	15, // 49: todo.v1.TodoService.UpdateTodo:output_type -> todo.v1.UpdateTodoResponse
+	17, // 50: todo.v1.TodoService.UpsertTodo:output_type -> todo.v1.UpsertTodoResponse
+	19, // 51: todo.v1.TodoService.DeleteTodo:output_type -> todo.v1.DeleteTodoResponse
+	22, // 52: todo.v1.TodoService.ShareTodo:output_type -> todo.v1.ShareTodoResponse
+	24, // 53: todo.v1.TodoService.UnshareTodo:output_type -> todo.v1.UnshareTodoResponse
+	26, // 54: todo.v1.TodoService.ListTodoGrants:output_type -> todo.v1.ListTodoGrantsResponse
+	35, // 55: todo.v1.TodoService.WatchTodos:output_type -> todo.v1.TodoEvent
+	38, // 56: todo.v1.TodoService.ImportTodos:output_type -> todo.v1.ImportTodosResponse
+	4,  // 57: todo.v1.TodoService.ExportTodos:output_type -> todo.v1.Todo
+	29, // 58: todo.v1.UserService.CreateUser:output_type -> todo.v1.CreateUserResponse
+	31, // 59: todo.v1.UserService.GetUser:output_type -> todo.v1.GetUserResponse
+	33, // 60: todo.v1.UserService.ListUsers:output_type -> todo.v1.ListUsersResponse
+	45, // [45:61] is the sub-list for method output_type
+	29, // [29:45] is the sub-list for method input_type
+	29, // [29:29] is the sub-list for extension type_name
+	29, // [29:29] is the sub-list for extension extendee
+	0,  // [0:29] is the sub-list for field type_name
 }
 
 func init() { file_todo_proto_init() }
@@ -847,10 +2615,10 @@ func file_todo_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_todo_proto_rawDesc), len(file_todo_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   13,
+			NumEnums:      4,
+			NumMessages:   36,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_todo_proto_goTypes,
 		DependencyIndexes: file_todo_proto_depIdxs,
@@ -24,7 +24,14 @@ const (
 	TodoService_GetTodo_FullMethodName         = "/todo.v1.TodoService/GetTodo"
 	TodoService_ListTodos_FullMethodName       = "/todo.v1.TodoService/ListTodos"
 	TodoService_UpdateTodo_FullMethodName      = "/todo.v1.TodoService/UpdateTodo"
+	TodoService_UpsertTodo_FullMethodName      = "/todo.v1.TodoService/UpsertTodo"
 	TodoService_DeleteTodo_FullMethodName      = "/todo.v1.TodoService/DeleteTodo"
+	TodoService_ShareTodo_FullMethodName       = "/todo.v1.TodoService/ShareTodo"
+	TodoService_UnshareTodo_FullMethodName     = "/todo.v1.TodoService/UnshareTodo"
+	TodoService_ListTodoGrants_FullMethodName  = "/todo.v1.TodoService/ListTodoGrants"
+	TodoService_WatchTodos_FullMethodName      = "/todo.v1.TodoService/WatchTodos"
+	TodoService_ImportTodos_FullMethodName     = "/todo.v1.TodoService/ImportTodos"
+	TodoService_ExportTodos_FullMethodName     = "/todo.v1.TodoService/ExportTodos"
 )
 
 // TodoServiceClient is the client API for TodoService service.
@@ -36,7 +43,22 @@ type TodoServiceClient interface {
 	GetTodo(ctx context.Context, in *GetTodoRequest, opts ...grpc.CallOption) (*GetTodoResponse, error)
 	ListTodos(ctx context.Context, in *ListTodosRequest, opts ...grpc.CallOption) (*ListTodosResponse, error)
 	UpdateTodo(ctx context.Context, in *UpdateTodoRequest, opts ...grpc.CallOption) (*UpdateTodoResponse, error)
+	UpsertTodo(ctx context.Context, in *UpsertTodoRequest, opts ...grpc.CallOption) (*UpsertTodoResponse, error)
 	DeleteTodo(ctx context.Context, in *DeleteTodoRequest, opts ...grpc.CallOption) (*DeleteTodoResponse, error)
+	ShareTodo(ctx context.Context, in *ShareTodoRequest, opts ...grpc.CallOption) (*ShareTodoResponse, error)
+	UnshareTodo(ctx context.Context, in *UnshareTodoRequest, opts ...grpc.CallOption) (*UnshareTodoResponse, error)
+	ListTodoGrants(ctx context.Context, in *ListTodoGrantsRequest, opts ...grpc.CallOption) (*ListTodoGrantsResponse, error)
+	// Streams changes as they happen. Ends with OUT_OF_RANGE when the
+	// resume token has expired and RESOURCE_EXHAUSTED when the client falls
+	// behind; resume with the last token in the latter case.
+	WatchTodos(ctx context.Context, in *WatchTodosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TodoEvent], error)
+	// Creates todos from a stream of any length. Items are committed in
+	// batches as they arrive; invalid items are reported in the response
+	// without stopping the import.
+	ImportTodos(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportTodosRequest, ImportTodosResponse], error)
+	// Streams every todo matching the filters, newest first, from a single
+	// consistent snapshot.
+	ExportTodos(ctx context.Context, in *ExportTodosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Todo], error)
 }
 
 type todoServiceClient struct {
@@ -97,6 +119,16 @@ func (c *todoServiceClient) UpdateTodo(ctx context.Context, in *UpdateTodoReques
 	return out, nil
 }
 
+func (c *todoServiceClient) UpsertTodo(ctx context.Context, in *UpsertTodoRequest, opts ...grpc.CallOption) (*UpsertTodoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpsertTodoResponse)
+	err := c.cc.Invoke(ctx, TodoService_UpsertTodo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *todoServiceClient) DeleteTodo(ctx context.Context, in *DeleteTodoRequest, opts ...grpc.CallOption) (*DeleteTodoResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeleteTodoResponse)
@@ -107,6 +139,87 @@ func (c *todoServiceClient) DeleteTodo(ctx context.Context, in *DeleteTodoReques
 	return out, nil
 }
 
+func (c *todoServiceClient) ShareTodo(ctx context.Context, in *ShareTodoRequest, opts ...grpc.CallOption) (*ShareTodoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShareTodoResponse)
+	err := c.cc.Invoke(ctx, TodoService_ShareTodo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) UnshareTodo(ctx context.Context, in *UnshareTodoRequest, opts ...grpc.CallOption) (*UnshareTodoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnshareTodoResponse)
+	err := c.cc.Invoke(ctx, TodoService_UnshareTodo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListTodoGrants(ctx context.Context, in *ListTodoGrantsRequest, opts ...grpc.CallOption) (*ListTodoGrantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTodoGrantsResponse)
+	err := c.cc.Invoke(ctx, TodoService_ListTodoGrants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) WatchTodos(ctx context.Context, in *WatchTodosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TodoEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TodoService_ServiceDesc.Streams[0], TodoService_WatchTodos_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTodosRequest, TodoEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TodoService_WatchTodosClient = grpc.ServerStreamingClient[TodoEvent]
+
+func (c *todoServiceClient) ImportTodos(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportTodosRequest, ImportTodosResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TodoService_ServiceDesc.Streams[1], TodoService_ImportTodos_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportTodosRequest, ImportTodosResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TodoService_ImportTodosClient = grpc.ClientStreamingClient[ImportTodosRequest, ImportTodosResponse]
+
+func (c *todoServiceClient) ExportTodos(ctx context.Context, in *ExportTodosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Todo], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TodoService_ServiceDesc.Streams[2], TodoService_ExportTodos_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportTodosRequest, Todo]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TodoService_ExportTodosClient = grpc.ServerStreamingClient[Todo]
+
 // TodoServiceServer is the server API for TodoService service.
 // All implementations must embed UnimplementedTodoServiceServer
 // for forward compatibility.
@@ -116,7 +229,22 @@ type TodoServiceServer interface {
 	GetTodo(context.Context, *GetTodoRequest) (*GetTodoResponse, error)
 	ListTodos(context.Context, *ListTodosRequest) (*ListTodosResponse, error)
 	UpdateTodo(context.Context, *UpdateTodoRequest) (*UpdateTodoResponse, error)
+	UpsertTodo(context.Context, *UpsertTodoRequest) (*UpsertTodoResponse, error)
 	DeleteTodo(context.Context, *DeleteTodoRequest) (*DeleteTodoResponse, error)
+	ShareTodo(context.Context, *ShareTodoRequest) (*ShareTodoResponse, error)
+	UnshareTodo(context.Context, *UnshareTodoRequest) (*UnshareTodoResponse, error)
+	ListTodoGrants(context.Context, *ListTodoGrantsRequest) (*ListTodoGrantsResponse, error)
+	// Streams changes as they happen. Ends with OUT_OF_RANGE when the
+	// resume token has expired and RESOURCE_EXHAUSTED when the client falls
+	// behind; resume with the last token in the latter case.
+	WatchTodos(*WatchTodosRequest, grpc.ServerStreamingServer[TodoEvent]) error
+	// Creates todos from a stream of any length. Items are committed in
+	// batches as they arrive; invalid items are reported in the response
+	// without stopping the import.
+	ImportTodos(grpc.ClientStreamingServer[ImportTodosRequest, ImportTodosResponse]) error
+	// Streams every todo matching the filters, newest first, from a single
+	// consistent snapshot.
+	ExportTodos(*ExportTodosRequest, grpc.ServerStreamingServer[Todo]) error
 	mustEmbedUnimplementedTodoServiceServer()
 }
 
@@ -142,9 +270,30 @@ func (UnimplementedTodoServiceServer) ListTodos(context.Context, *ListTodosReque
 func (UnimplementedTodoServiceServer) UpdateTodo(context.Context, *UpdateTodoRequest) (*UpdateTodoResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method UpdateTodo not implemented")
 }
+func (UnimplementedTodoServiceServer) UpsertTodo(context.Context, *UpsertTodoRequest) (*UpsertTodoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpsertTodo not implemented")
+}
 func (UnimplementedTodoServiceServer) DeleteTodo(context.Context, *DeleteTodoRequest) (*DeleteTodoResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeleteTodo not implemented")
 }
+func (UnimplementedTodoServiceServer) ShareTodo(context.Context, *ShareTodoRequest) (*ShareTodoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ShareTodo not implemented")
+}
+func (UnimplementedTodoServiceServer) UnshareTodo(context.Context, *UnshareTodoRequest) (*UnshareTodoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnshareTodo not implemented")
+}
+func (UnimplementedTodoServiceServer) ListTodoGrants(context.Context, *ListTodoGrantsRequest) (*ListTodoGrantsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTodoGrants not implemented")
+}
+func (UnimplementedTodoServiceServer) WatchTodos(*WatchTodosRequest, grpc.ServerStreamingServer[TodoEvent]) error {
+	return status.Error(codes.Unimplemented, "method WatchTodos not implemented")
+}
+func (UnimplementedTodoServiceServer) ImportTodos(grpc.ClientStreamingServer[ImportTodosRequest, ImportTodosResponse]) error {
+	return status.Error(codes.Unimplemented, "method ImportTodos not implemented")
+}
+func (UnimplementedTodoServiceServer) ExportTodos(*ExportTodosRequest, grpc.ServerStreamingServer[Todo]) error {
+	return status.Error(codes.Unimplemented, "method ExportTodos not implemented")
+}
 func (UnimplementedTodoServiceServer) mustEmbedUnimplementedTodoServiceServer() {}
 func (UnimplementedTodoServiceServer) testEmbeddedByValue()                     {}
 
@@ -256,6 +405,24 @@ func _TodoService_UpdateTodo_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TodoService_UpsertTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).UpsertTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_UpsertTodo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).UpsertTodo(ctx, req.(*UpsertTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TodoService_DeleteTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteTodoRequest)
 	if err := dec(in); err != nil {
@@ -274,6 +441,89 @@ func _TodoService_DeleteTodo_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TodoService_ShareTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ShareTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_ShareTodo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ShareTodo(ctx, req.(*ShareTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_UnshareTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnshareTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).UnshareTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_UnshareTodo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).UnshareTodo(ctx, req.(*UnshareTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListTodoGrants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodoGrantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListTodoGrants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_ListTodoGrants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListTodoGrants(ctx, req.(*ListTodoGrantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_WatchTodos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTodosRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TodoServiceServer).WatchTodos(m, &grpc.GenericServerStream[WatchTodosRequest, TodoEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TodoService_WatchTodosServer = grpc.ServerStreamingServer[TodoEvent]
+
+func _TodoService_ImportTodos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TodoServiceServer).ImportTodos(&grpc.GenericServerStream[ImportTodosRequest, ImportTodosResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TodoService_ImportTodosServer = grpc.ClientStreamingServer[ImportTodosRequest, ImportTodosResponse]
+
+func _TodoService_ExportTodos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportTodosRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TodoServiceServer).ExportTodos(m, &grpc.GenericServerStream[ExportTodosRequest, Todo]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TodoService_ExportTodosServer = grpc.ServerStreamingServer[Todo]
+
 // TodoService_ServiceDesc is the grpc.ServiceDesc for TodoService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -301,10 +551,220 @@ var TodoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateTodo",
 			Handler:    _TodoService_UpdateTodo_Handler,
 		},
+		{
+			MethodName: "UpsertTodo",
+			Handler:    _TodoService_UpsertTodo_Handler,
+		},
 		{
 			MethodName: "DeleteTodo",
 			Handler:    _TodoService_DeleteTodo_Handler,
 		},
+		{
+			MethodName: "ShareTodo",
+			Handler:    _TodoService_ShareTodo_Handler,
+		},
+		{
+			MethodName: "UnshareTodo",
+			Handler:    _TodoService_UnshareTodo_Handler,
+		},
+		{
+			MethodName: "ListTodoGrants",
+			Handler:    _TodoService_ListTodoGrants_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTodos",
+			Handler:       _TodoService_WatchTodos_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportTodos",
+			Handler:       _TodoService_ImportTodos_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportTodos",
+			Handler:       _TodoService_ExportTodos_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "todo.proto",
+}
+
+const (
+	UserService_CreateUser_FullMethodName = "/todo.v1.UserService/CreateUser"
+	UserService_GetUser_FullMethodName    = "/todo.v1.UserService/GetUser"
+	UserService_ListUsers_FullMethodName  = "/todo.v1.UserService/ListUsers"
+)
+
+// UserServiceClient is the client API for UserService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type UserServiceClient interface {
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error)
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+}
+
+type userServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserServiceClient(cc grpc.ClientConnInterface) UserServiceClient {
+	return &userServiceClient{cc}
+}
+
+func (c *userServiceClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateUserResponse)
+	err := c.cc.Invoke(ctx, UserService_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserResponse)
+	err := c.cc.Invoke(ctx, UserService_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, UserService_ListUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserServiceServer is the server API for UserService service.
+// All implementations must embed UnimplementedUserServiceServer
+// for forward compatibility.
+type UserServiceServer interface {
+	CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error)
+	GetUser(context.Context, *GetUserRequest) (*GetUserResponse, error)
+	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+// UnimplementedUserServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserServiceServer struct{}
+
+func (UnimplementedUserServiceServer) CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedUserServiceServer) GetUser(context.Context, *GetUserRequest) (*GetUserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedUserServiceServer) ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
+func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserServiceServer will
+// result in compilation errors.
+type UnsafeUserServiceServer interface {
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+func RegisterUserServiceServer(s grpc.ServiceRegistrar, srv UserServiceServer) {
+	// If the following call panics, it indicates UnimplementedUserServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserService_ServiceDesc, srv)
+}
+
+func _UserService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "todo.v1.UserService",
+	HandlerType: (*UserServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateUser",
+			Handler:    _UserService_CreateUser_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _UserService_GetUser_Handler,
+		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _UserService_ListUsers_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "todo.proto",
@@ -0,0 +1,772 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.32.0
+// source: todo/v2/todo.proto
+
+package todo_v2
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Status int32
+
+const (
+	Status_STATUS_UNSPECIFIED Status = 0
+	Status_STATUS_PENDING     Status = 1
+	Status_STATUS_DONE        Status = 2
+)
+
+// Enum value maps for Status.
+var (
+	Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_PENDING",
+		2: "STATUS_DONE",
+	}
+	Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"STATUS_PENDING":     1,
+		"STATUS_DONE":        2,
+	}
+)
+
+func (x Status) Enum() *Status {
+	p := new(Status)
+	*p = x
+	return p
+}
+
+func (x Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v2_todo_proto_enumTypes[0].Descriptor()
+}
+
+func (Status) Type() protoreflect.EnumType {
+	return &file_todo_v2_todo_proto_enumTypes[0]
+}
+
+func (x Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Status.Descriptor instead.
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{0}
+}
+
+type Todo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Output only on create unless supplied as todo_id.
+	Id          string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TenantId    string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Title       string `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description string `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Status      Status `protobuf:"varint,5,opt,name=status,proto3,enum=todo.v2.Status" json:"status,omitempty"`
+	// Empty when the todo was created anonymously.
+	CreatedBy     string                 `protobuf:"bytes,6,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	Assignees     []string               `protobuf:"bytes,7,rep,name=assignees,proto3" json:"assignees,omitempty"`
+	CreateTime    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	UpdateTime    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Todo) Reset() {
+	*x = Todo{}
+	mi := &file_todo_v2_todo_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Todo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Todo) ProtoMessage() {}
+
+func (x *Todo) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Todo.ProtoReflect.Descriptor instead.
+func (*Todo) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Todo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Todo) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *Todo) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Todo) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Todo) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *Todo) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *Todo) GetAssignees() []string {
+	if x != nil {
+		return x.Assignees
+	}
+	return nil
+}
+
+func (x *Todo) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *Todo) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+type CreateTodoRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only title, description and assignees are read.
+	Todo *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	// Optional client-supplied UUID; generated by the server when empty.
+	TodoId        string `protobuf:"bytes,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTodoRequest) Reset() {
+	*x = CreateTodoRequest{}
+	mi := &file_todo_v2_todo_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTodoRequest) ProtoMessage() {}
+
+func (x *CreateTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTodoRequest.ProtoReflect.Descriptor instead.
+func (*CreateTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateTodoRequest) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+func (x *CreateTodoRequest) GetTodoId() string {
+	if x != nil {
+		return x.TodoId
+	}
+	return ""
+}
+
+type BatchCreateTodosRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Created all-or-nothing.
+	Requests      []*CreateTodoRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateTodosRequest) Reset() {
+	*x = BatchCreateTodosRequest{}
+	mi := &file_todo_v2_todo_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateTodosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateTodosRequest) ProtoMessage() {}
+
+func (x *BatchCreateTodosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateTodosRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateTodosRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BatchCreateTodosRequest) GetRequests() []*CreateTodoRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type BatchCreateTodosResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Todos         []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateTodosResponse) Reset() {
+	*x = BatchCreateTodosResponse{}
+	mi := &file_todo_v2_todo_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateTodosResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateTodosResponse) ProtoMessage() {}
+
+func (x *BatchCreateTodosResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateTodosResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateTodosResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchCreateTodosResponse) GetTodos() []*Todo {
+	if x != nil {
+		return x.Todos
+	}
+	return nil
+}
+
+type GetTodoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTodoRequest) Reset() {
+	*x = GetTodoRequest{}
+	mi := &file_todo_v2_todo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTodoRequest) ProtoMessage() {}
+
+func (x *GetTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTodoRequest.ProtoReflect.Descriptor instead.
+func (*GetTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetTodoRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListTodosRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to 50; values above 200 are capped.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token from a previous call with the same filters.
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// User ID filters; "me" resolves to the calling user.
+	CreatedBy     string `protobuf:"bytes,3,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	AssignedTo    string `protobuf:"bytes,4,opt,name=assigned_to,json=assignedTo,proto3" json:"assigned_to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTodosRequest) Reset() {
+	*x = ListTodosRequest{}
+	mi := &file_todo_v2_todo_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTodosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTodosRequest) ProtoMessage() {}
+
+func (x *ListTodosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTodosRequest.ProtoReflect.Descriptor instead.
+func (*ListTodosRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListTodosRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTodosRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListTodosRequest) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *ListTodosRequest) GetAssignedTo() string {
+	if x != nil {
+		return x.AssignedTo
+	}
+	return ""
+}
+
+type ListTodosResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Todos []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"`
+	// Empty when there are no more results.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTodosResponse) Reset() {
+	*x = ListTodosResponse{}
+	mi := &file_todo_v2_todo_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTodosResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTodosResponse) ProtoMessage() {}
+
+func (x *ListTodosResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTodosResponse.ProtoReflect.Descriptor instead.
+func (*ListTodosResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListTodosResponse) GetTodos() []*Todo {
+	if x != nil {
+		return x.Todos
+	}
+	return nil
+}
+
+func (x *ListTodosResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type UpdateTodoRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The todo to update, identified by id.
+	Todo *Todo `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	// Fields to write: "title", "description", "status" and "assignees".
+	// Required; masked fields are written exactly, so empty values clear them.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTodoRequest) Reset() {
+	*x = UpdateTodoRequest{}
+	mi := &file_todo_v2_todo_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTodoRequest) ProtoMessage() {}
+
+func (x *UpdateTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTodoRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateTodoRequest) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+func (x *UpdateTodoRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type DeleteTodoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTodoRequest) Reset() {
+	*x = DeleteTodoRequest{}
+	mi := &file_todo_v2_todo_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTodoRequest) ProtoMessage() {}
+
+func (x *DeleteTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTodoRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteTodoRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteTodoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTodoResponse) Reset() {
+	*x = DeleteTodoResponse{}
+	mi := &file_todo_v2_todo_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTodoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTodoResponse) ProtoMessage() {}
+
+func (x *DeleteTodoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTodoResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTodoResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{9}
+}
+
+var File_todo_v2_todo_proto protoreflect.FileDescriptor
+
+const file_todo_v2_todo_proto_rawDesc = "" +
+	"\n" +
+	"\x12todo/v2/todo.proto\x12\atodo.v2\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xcb\x02\n" +
+	"\x04Todo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12'\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x0f.todo.v2.StatusR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x06 \x01(\tR\tcreatedBy\x12\x1c\n" +
+	"\tassignees\x18\a \x03(\tR\tassignees\x12;\n" +
+	"\vcreate_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12;\n" +
+	"\vupdate_time\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"updateTime\"O\n" +
+	"\x11CreateTodoRequest\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v2.TodoR\x04todo\x12\x17\n" +
+	"\atodo_id\x18\x02 \x01(\tR\x06todoId\"Q\n" +
+	"\x17BatchCreateTodosRequest\x126\n" +
+	"\brequests\x18\x01 \x03(\v2\x1a.todo.v2.CreateTodoRequestR\brequests\"?\n" +
+	"\x18BatchCreateTodosResponse\x12#\n" +
+	"\x05todos\x18\x01 \x03(\v2\r.todo.v2.TodoR\x05todos\" \n" +
+	"\x0eGetTodoRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x8e\x01\n" +
+	"\x10ListTodosRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x03 \x01(\tR\tcreatedBy\x12\x1f\n" +
+	"\vassigned_to\x18\x04 \x01(\tR\n" +
+	"assignedTo\"`\n" +
+	"\x11ListTodosResponse\x12#\n" +
+	"\x05todos\x18\x01 \x03(\v2\r.todo.v2.TodoR\x05todos\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"s\n" +
+	"\x11UpdateTodoRequest\x12!\n" +
+	"\x04todo\x18\x01 \x01(\v2\r.todo.v2.TodoR\x04todo\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"#\n" +
+	"\x11DeleteTodoRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x14\n" +
+	"\x12DeleteTodoResponse*E\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eSTATUS_PENDING\x10\x01\x12\x0f\n" +
+	"\vSTATUS_DONE\x10\x022\x96\x03\n" +
+	"\vTodoService\x127\n" +
+	"\n" +
+	"CreateTodo\x12\x1a.todo.v2.CreateTodoRequest\x1a\r.todo.v2.Todo\x12W\n" +
+	"\x10BatchCreateTodos\x12 .todo.v2.BatchCreateTodosRequest\x1a!.todo.v2.BatchCreateTodosResponse\x121\n" +
+	"\aGetTodo\x12\x17.todo.v2.GetTodoRequest\x1a\r.todo.v2.Todo\x12B\n" +
+	"\tListTodos\x12\x19.todo.v2.ListTodosRequest\x1a\x1a.todo.v2.ListTodosResponse\x127\n" +
+	"\n" +
+	"UpdateTodo\x12\x1a.todo.v2.UpdateTodoRequest\x1a\r.todo.v2.Todo\x12E\n" +
+	"\n" +
+	"DeleteTodo\x12\x1a.todo.v2.DeleteTodoRequest\x1a\x1b.todo.v2.DeleteTodoResponseB?Z=github.com/fuzail-ahmed/codex-test/shared/gen/todo/v2;todo_v2b\x06proto3"
+
+var (
+	file_todo_v2_todo_proto_rawDescOnce sync.Once
+	file_todo_v2_todo_proto_rawDescData []byte
+)
+
+func file_todo_v2_todo_proto_rawDescGZIP() []byte {
+	file_todo_v2_todo_proto_rawDescOnce.Do(func() {
+		file_todo_v2_todo_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_todo_v2_todo_proto_rawDesc), len(file_todo_v2_todo_proto_rawDesc)))
+	})
+	return file_todo_v2_todo_proto_rawDescData
+}
+
+var file_todo_v2_todo_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_todo_v2_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_todo_v2_todo_proto_goTypes = []any{
+	(Status)(0),                      // 0: todo.v2.Status
+	(*Todo)(nil),                     // 1: todo.v2.Todo
+	(*CreateTodoRequest)(nil),        // 2: todo.v2.CreateTodoRequest
+	(*BatchCreateTodosRequest)(nil),  // 3: todo.v2.BatchCreateTodosRequest
+	(*BatchCreateTodosResponse)(nil), // 4: todo.v2.BatchCreateTodosResponse
+	(*GetTodoRequest)(nil),           // 5: todo.v2.GetTodoRequest
+	(*ListTodosRequest)(nil),         // 6: todo.v2.ListTodosRequest
+	(*ListTodosResponse)(nil),        // 7: todo.v2.ListTodosResponse
+	(*UpdateTodoRequest)(nil),        // 8: todo.v2.UpdateTodoRequest
+	(*DeleteTodoRequest)(nil),        // 9: todo.v2.DeleteTodoRequest
+	(*DeleteTodoResponse)(nil),       // 10: todo.v2.DeleteTodoResponse
+	(*timestamppb.Timestamp)(nil),    // 11: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),    // 12: google.protobuf.FieldMask
+}
+var file_todo_v2_todo_proto_depIdxs = []int32{
+	0,  // 0: todo.v2.Todo.status:type_name -> todo.v2.Status
+	11, // 1: todo.v2.Todo.create_time:type_name -> google.protobuf.Timestamp
+	11, // 2: todo.v2.Todo.update_time:type_name -> google.protobuf.Timestamp
+	1,  // 3: todo.v2.CreateTodoRequest.todo:type_name -> todo.v2.Todo
+	2,  // 4: todo.v2.BatchCreateTodosRequest.requests:type_name -> todo.v2.CreateTodoRequest
+	1,  // 5: todo.v2.BatchCreateTodosResponse.todos:type_name -> todo.v2.Todo
+	1,  // 6: todo.v2.ListTodosResponse.todos:type_name -> todo.v2.Todo
+	1,  // 7: todo.v2.UpdateTodoRequest.todo:type_name -> todo.v2.Todo
+	12, // 8: todo.v2.UpdateTodoRequest.update_mask:type_name -> google.protobuf.FieldMask
+	2,  // 9: todo.v2.TodoService.CreateTodo:input_type -> todo.v2.CreateTodoRequest
+	3,  // 10: todo.v2.TodoService.BatchCreateTodos:input_type -> todo.v2.BatchCreateTodosRequest
+	5,  // 11: todo.v2.TodoService.GetTodo:input_type -> todo.v2.GetTodoRequest
+	6,  // 12: todo.v2.TodoService.ListTodos:input_type -> todo.v2.ListTodosRequest
+	8,  // 13: todo.v2.TodoService.UpdateTodo:input_type -> todo.v2.UpdateTodoRequest
+	9,  // 14: todo.v2.TodoService.DeleteTodo:input_type -> todo.v2.DeleteTodoRequest
+	1,  // 15: todo.v2.TodoService.CreateTodo:output_type -> todo.v2.Todo
+	4,  // 16: todo.v2.TodoService.BatchCreateTodos:output_type -> todo.v2.BatchCreateTodosResponse
+	1,  // 17: todo.v2.TodoService.GetTodo:output_type -> todo.v2.Todo
+	7,  // 18: todo.v2.TodoService.ListTodos:output_type -> todo.v2.ListTodosResponse
+	1,  // 19: todo.v2.TodoService.UpdateTodo:output_type -> todo.v2.Todo
+	10, // 20: todo.v2.TodoService.DeleteTodo:output_type -> todo.v2.DeleteTodoResponse
+	15, // [15:21] is the sub-list for method output_type
+	9,  // [9:15] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_todo_v2_todo_proto_init() }
+func file_todo_v2_todo_proto_init() {
+	if File_todo_v2_todo_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_todo_v2_todo_proto_rawDesc), len(file_todo_v2_todo_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_todo_v2_todo_proto_goTypes,
+		DependencyIndexes: file_todo_v2_todo_proto_depIdxs,
+		EnumInfos:         file_todo_v2_todo_proto_enumTypes,
+		MessageInfos:      file_todo_v2_todo_proto_msgTypes,
+	}.Build()
+	File_todo_v2_todo_proto = out.File
+	file_todo_v2_todo_proto_goTypes = nil
+	file_todo_v2_todo_proto_depIdxs = nil
+}